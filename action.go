@@ -0,0 +1,39 @@
+// Copyright (c) 2022 0x9ef. All rights reserved.
+// Use of this source code is governed by an MIT license
+// that can be found in the LICENSE file.
+package ethernet
+
+import "io"
+
+// Categories of the action frames (SubtypeAction).
+const (
+	ActionSpectrumManagement uint8 = 0
+	ActionQoS                uint8 = 1
+	ActionDLS                uint8 = 2
+	ActionBlockAck           uint8 = 3
+	ActionPublic             uint8 = 4
+	ActionRadioMeasurement   uint8 = 5
+	ActionHT                 uint8 = 7
+	ActionSAQuery            uint8 = 8
+	ActionVHT                uint8 = 21
+)
+
+// Action codes of the ActionBlockAck category.
+const (
+	BlockAckAddBARequest  uint8 = 0
+	BlockAckAddBAResponse uint8 = 1
+	BlockAckDelBA         uint8 = 2
+)
+
+// minActionSize is category + action code.
+const minActionSize = 2
+
+// ParseActionFrame decodes the body of action frame (SubtypeAction) into the category,
+// the action code and the action specific body, which aliases payload.
+// Returns io.ErrUnexpectedEOF if the payload is shorter than 2 bytes.
+func ParseActionFrame(payload []byte) (category uint8, action uint8, body []byte, err error) {
+	if len(payload) < minActionSize {
+		return 0, 0, nil, io.ErrUnexpectedEOF
+	}
+	return payload[0], payload[1], payload[minActionSize:], nil
+}
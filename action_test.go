@@ -0,0 +1,61 @@
+package ethernet
+
+import (
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseActionFrame(t *testing.T) {
+	// ADDBA request: dialog token, block ack parameter set, timeout, starting sequence control
+	addba := []byte{ActionBlockAck, BlockAckAddBARequest, 0x01, 0x02, 0x10, 0x00, 0x00, 0x00, 0x00}
+
+	type suite struct {
+		name         string
+		payload      []byte
+		wantCategory uint8
+		wantAction   uint8
+		wantBody     []byte
+		wantErr      error
+	}
+
+	testCases := []suite{
+		{
+			name:         "positive_block_ack",
+			payload:      addba,
+			wantCategory: ActionBlockAck,
+			wantAction:   BlockAckAddBARequest,
+			wantBody:     addba[2:],
+		},
+		{
+			name:         "positive_empty_body",
+			payload:      []byte{ActionSAQuery, 0x01},
+			wantCategory: ActionSAQuery,
+			wantAction:   0x01,
+			wantBody:     []byte{},
+		},
+		{
+			name:    "negative_no_action_code",
+			payload: []byte{ActionBlockAck},
+			wantErr: io.ErrUnexpectedEOF,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			f := NewFrame80211(HardwareAddr{1, 1, 1, 1, 1, 1}, HardwareAddr{2, 2, 2, 2, 2, 2}, HardwareAddr{1, 1, 1, 1, 1, 1}, nil,
+				Encode80211Fc(0, uint16(Management), SubtypeAction, 0, 0, 0, 0, 0, 0, 0, 0), 0, tc.payload)
+			decoded, err := Unmarshal80211(f.Marshal())
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			category, action, body, err := ParseActionFrame(decoded.Payload())
+			assert.Equal(t, tc.wantErr, err)
+			assert.Equal(t, tc.wantCategory, category)
+			assert.Equal(t, tc.wantAction, action)
+			assert.Equal(t, tc.wantBody, body)
+		})
+	}
+}
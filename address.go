@@ -5,6 +5,8 @@ package ethernet
 
 import (
 	"bytes"
+	"crypto/subtle"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"strconv"
@@ -28,7 +30,27 @@ func NewHardwareAddr(b0, b1, b2, b3, b4, b5 byte) HardwareAddr {
 	return HardwareAddr{b0, b1, b2, b3, b4, b5}
 }
 
-// ParseHardwareAddr parses a MAC address from the string and return HardwareAddr
+// ErrInvalidAddrLen is returned when the MAC address is not 6 bytes long.
+var ErrInvalidAddrLen = errors.New("hardware address must be 6 bytes")
+
+// Bytes returns the address as a slice sharing the memory with h, without copying.
+func (h *HardwareAddr) Bytes() []byte { return h[:] }
+
+// SetBytes sets the address from b, returns ErrInvalidAddrLen if b is not 6 bytes long.
+func (h *HardwareAddr) SetBytes(b []byte) error {
+	if len(b) != len(h) {
+		return ErrInvalidAddrLen
+	}
+	copy(h[:], b)
+	return nil
+}
+
+// ErrInvalidAddrGroup is returned when a group of the MAC address is not 1 or 2 hexadecimal digits.
+var ErrInvalidAddrGroup = errors.New("hardware address group must be 1 or 2 hex digits")
+
+// ParseHardwareAddr parses a MAC address from the string and return HardwareAddr.
+// Each of 6 colon separated groups is 1 or 2 hexadecimal digits, so leading zeros
+// may be omitted ("1:2:3:4:5:6" is 01:02:03:04:05:06).
 func ParseHardwareAddr(addr string) (HardwareAddr, error) {
 	b := strings.SplitN(addr, ":", 6)
 	if len(b) != 6 {
@@ -36,7 +58,10 @@ func ParseHardwareAddr(addr string) (HardwareAddr, error) {
 	}
 	var haddr HardwareAddr
 	for i := range b {
-		v, err := strconv.ParseUint(b[i], 16, 16)
+		if len(b[i]) < 1 || len(b[i]) > 2 {
+			return HardwareAddr{}, ErrInvalidAddrGroup
+		}
+		v, err := strconv.ParseUint(b[i], 16, 8)
 		if err != nil {
 			return HardwareAddr{}, err
 		}
@@ -45,12 +70,45 @@ func ParseHardwareAddr(addr string) (HardwareAddr, error) {
 	return haddr, nil
 }
 
+// ErrInvalidCiscoAddr is returned when the string is not a MAC address in the Cisco format.
+var ErrInvalidCiscoAddr = errors.New("invalid Cisco hardware address, want aabb.ccdd.eeff")
+
+// ParseCiscoHardwareAddr strictly parses a MAC address in the dotted Cisco format
+// aabb.ccdd.eeff: three groups of exactly four hexadecimal digits (any case).
+func ParseCiscoHardwareAddr(addr string) (HardwareAddr, error) {
+	if len(addr) != 14 || addr[4] != '.' || addr[9] != '.' {
+		return HardwareAddr{}, ErrInvalidCiscoAddr
+	}
+	var haddr HardwareAddr
+	if _, err := hex.Decode(haddr[:], []byte(addr[0:4]+addr[5:9]+addr[10:14])); err != nil {
+		return HardwareAddr{}, ErrInvalidCiscoAddr
+	}
+	return haddr, nil
+}
+
+// CiscoString formats the MAC address in the dotted Cisco format aabb.ccdd.eeff
+func (h HardwareAddr) CiscoString() string {
+	return fmt.Sprintf("%.2x%.2x.%.2x%.2x.%.2x%.2x",
+		h[0], h[1], h[2], h[3], h[4], h[5],
+	)
+}
+
 // Organisationally Unique Identifier
 func (h HardwareAddr) Oui() [3]byte { return [3]byte{h[0], h[1], h[2]} }
 
 // Network Interface Controller
 func (h HardwareAddr) Nic() [3]byte { return [3]byte{h[3], h[4], h[5]} }
 
+// OuiString formats the Organisationally Unique Identifier as aa:bb:cc
+func (h HardwareAddr) OuiString() string {
+	return fmt.Sprintf("%.2x:%.2x:%.2x", h[0], h[1], h[2])
+}
+
+// NicString formats the Network Interface Controller part as dd:ee:ff
+func (h HardwareAddr) NicString() string {
+	return fmt.Sprintf("%.2x:%.2x:%.2x", h[3], h[4], h[5])
+}
+
 // String stringifies hexadecimal MAC address to output string.
 // You have to manually check if the mac address is correct
 func (h HardwareAddr) String() string {
@@ -64,7 +122,58 @@ func (h HardwareAddr) Compare(raddr HardwareAddr) bool {
 	return bytes.Compare(h[:], raddr[:]) == 0
 }
 
+// EqualConstantTime compares two MAC addresses for equality in constant time.
+// Use it when the comparison result gates access, for other cases use Compare.
+func (h HardwareAddr) EqualConstantTime(raddr HardwareAddr) bool {
+	return subtle.ConstantTimeCompare(h[:], raddr[:]) == 1
+}
+
+// IsMulticast returns true if the group (multicast) bit of the address is set,
+// broadcast address is a multicast address too.
+func (h HardwareAddr) IsMulticast() bool {
+	return h[0]&0x01 == 1
+}
+
+// IsBroadcast returns true if MAC address have only ones
+func (h HardwareAddr) IsBroadcast() bool {
+	return h == BroadcastAddr
+}
+
 // IsEmpty returns true if MAC address have only zeroes
 func (h HardwareAddr) IsEmpty() bool {
 	return h == EmptyAddr
 }
+
+// IsBridgeReserved reports whether the address is in the IEEE 802.1D reserved
+// multicast range 01:80:C2:00:00:00 - 01:80:C2:00:00:0F. Frames sent to these
+// addresses (STP, LLDP, PAE, etc) must not be forwarded by bridges.
+func (h HardwareAddr) IsBridgeReserved() bool {
+	return h[0] == 0x01 && h[1] == 0x80 && h[2] == 0xC2 &&
+		h[3] == 0x00 && h[4] == 0x00 && h[5] <= 0x0F
+}
+
+// ReservedName returns the name of the protocol using the reserved bridge address,
+// or empty string if the address isn't reserved or the slot is unassigned.
+func (h HardwareAddr) ReservedName() string {
+	if !h.IsBridgeReserved() {
+		return ""
+	}
+	switch h[5] {
+	case 0x00:
+		return "STP"
+	case 0x01:
+		return "MAC Control"
+	case 0x02:
+		return "Slow Protocols"
+	case 0x03:
+		return "802.1X PAE"
+	case 0x08:
+		return "Provider Bridge STP"
+	case 0x0D:
+		return "Provider Bridge MVRP"
+	case 0x0E:
+		return "LLDP"
+	default:
+		return ""
+	}
+}
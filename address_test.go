@@ -0,0 +1,161 @@
+package ethernet
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHardwareAddrOuiNicString(t *testing.T) {
+	h := HardwareAddr{0x8C, 0x8E, 0xC4, 0xFF, 0x9E, 0x0A}
+	assert.Equal(t, "8c:8e:c4", h.OuiString())
+	assert.Equal(t, "ff:9e:0a", h.NicString())
+	assert.Equal(t, h.String(), h.OuiString()+":"+h.NicString())
+}
+
+func TestHardwareAddrEqualConstantTime(t *testing.T) {
+	h := HardwareAddr{0x8C, 0x8E, 0xC4, 0xFF, 0x9E, 0x0A}
+	assert.True(t, h.EqualConstantTime(HardwareAddr{0x8C, 0x8E, 0xC4, 0xFF, 0x9E, 0x0A}))
+	assert.False(t, h.EqualConstantTime(HardwareAddr{0x8C, 0x8E, 0xC4, 0xFF, 0x9E, 0x0B}))
+	assert.False(t, h.EqualConstantTime(HardwareAddr{0x0C, 0x8E, 0xC4, 0xFF, 0x9E, 0x0A}))
+	assert.False(t, h.EqualConstantTime(EmptyAddr))
+}
+
+func TestHardwareAddrBridgeReserved(t *testing.T) {
+	type suite struct {
+		name         string
+		addr         HardwareAddr
+		wantReserved bool
+		wantName     string
+	}
+
+	testCases := []suite{
+		{name: "positive_stp", addr: HardwareAddr{0x01, 0x80, 0xC2, 0x00, 0x00, 0x00}, wantReserved: true, wantName: "STP"},
+		{name: "positive_lldp", addr: HardwareAddr{0x01, 0x80, 0xC2, 0x00, 0x00, 0x0E}, wantReserved: true, wantName: "LLDP"},
+		{name: "positive_unassigned", addr: HardwareAddr{0x01, 0x80, 0xC2, 0x00, 0x00, 0x0F}, wantReserved: true},
+		{name: "negative_out_of_range", addr: HardwareAddr{0x01, 0x80, 0xC2, 0x00, 0x00, 0x10}},
+		{name: "negative_broadcast", addr: BroadcastAddr},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.wantReserved, tc.addr.IsBridgeReserved())
+			assert.Equal(t, tc.wantName, tc.addr.ReservedName())
+		})
+	}
+}
+
+func TestHardwareAddrMulticastBroadcast(t *testing.T) {
+	type suite struct {
+		name          string
+		addr          HardwareAddr
+		wantMulticast bool
+		wantBroadcast bool
+	}
+
+	testCases := []suite{
+		{name: "positive_broadcast", addr: BroadcastAddr, wantMulticast: true, wantBroadcast: true},
+		{name: "positive_multicast", addr: HardwareAddr{0x01, 0x00, 0x5E, 0x00, 0x00, 0x01}, wantMulticast: true},
+		{name: "negative_unicast", addr: HardwareAddr{0x8C, 0x8E, 0xC4, 0xFF, 0x9E, 0x0A}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.wantMulticast, tc.addr.IsMulticast())
+			assert.Equal(t, tc.wantBroadcast, tc.addr.IsBroadcast())
+		})
+	}
+}
+
+func TestCiscoHardwareAddr(t *testing.T) {
+	type suite struct {
+		name     string
+		addr     string
+		wantAddr HardwareAddr
+		wantErr  error
+	}
+
+	testCases := []suite{
+		{name: "positive_lower", addr: "8c8e.c4ff.9e0a", wantAddr: HardwareAddr{0x8C, 0x8E, 0xC4, 0xFF, 0x9E, 0x0A}},
+		{name: "positive_upper", addr: "8C8E.C4FF.9E0A", wantAddr: HardwareAddr{0x8C, 0x8E, 0xC4, 0xFF, 0x9E, 0x0A}},
+		{name: "positive_zero", addr: "0000.0000.0000"},
+		{name: "negative_colons", addr: "8c:8e:c4:ff:9e:0a", wantErr: ErrInvalidCiscoAddr},
+		{name: "negative_short_group", addr: "8c8.ec4ff.9e0a", wantErr: ErrInvalidCiscoAddr},
+		{name: "negative_too_short", addr: "8c8e.c4ff.9e0", wantErr: ErrInvalidCiscoAddr},
+		{name: "negative_too_long", addr: "8c8e.c4ff.9e0a0", wantErr: ErrInvalidCiscoAddr},
+		{name: "negative_not_hex", addr: "8c8e.c4fg.9e0a", wantErr: ErrInvalidCiscoAddr},
+		{name: "negative_sign", addr: "+c8e.c4ff.9e0a", wantErr: ErrInvalidCiscoAddr},
+		{name: "negative_empty", wantErr: ErrInvalidCiscoAddr},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			h, err := ParseCiscoHardwareAddr(tc.addr)
+			assert.Equal(t, tc.wantErr, err)
+			assert.Equal(t, tc.wantAddr, h)
+			if err == nil {
+				assert.Equal(t, strings.ToLower(tc.addr), h.CiscoString())
+			}
+		})
+	}
+}
+
+func TestHardwareAddrBytes(t *testing.T) {
+	h := HardwareAddr{0x8C, 0x8E, 0xC4, 0xFF, 0x9E, 0x0A}
+	b := h.Bytes()
+	assert.Equal(t, []byte{0x8C, 0x8E, 0xC4, 0xFF, 0x9E, 0x0A}, b)
+	b[0] = 0x00
+	assert.Equal(t, byte(0x00), h[0], "Bytes must share the memory with the address")
+
+	type suite struct {
+		name     string
+		b        []byte
+		wantAddr HardwareAddr
+		wantErr  error
+	}
+
+	testCases := []suite{
+		{name: "positive_6_bytes", b: []byte{1, 2, 3, 4, 5, 6}, wantAddr: HardwareAddr{1, 2, 3, 4, 5, 6}},
+		{name: "negative_short", b: []byte{1, 2, 3, 4, 5}, wantAddr: h, wantErr: ErrInvalidAddrLen},
+		{name: "negative_long", b: []byte{1, 2, 3, 4, 5, 6, 7}, wantAddr: h, wantErr: ErrInvalidAddrLen},
+		{name: "negative_nil", wantAddr: h, wantErr: ErrInvalidAddrLen},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			addr := h
+			assert.Equal(t, tc.wantErr, addr.SetBytes(tc.b))
+			assert.Equal(t, tc.wantAddr, addr)
+		})
+	}
+}
+
+func TestParseHardwareAddr(t *testing.T) {
+	type suite struct {
+		name     string
+		addr     string
+		wantAddr HardwareAddr
+		wantErr  bool
+	}
+
+	testCases := []suite{
+		{name: "positive_two_digit_groups", addr: "8c:8e:c4:ff:9e:0a", wantAddr: HardwareAddr{0x8C, 0x8E, 0xC4, 0xFF, 0x9E, 0x0A}},
+		{name: "positive_single_digit_groups", addr: "1:2:3:4:5:6", wantAddr: HardwareAddr{1, 2, 3, 4, 5, 6}},
+		{name: "positive_mixed_groups", addr: "0:1b:21:a:F:ff", wantAddr: HardwareAddr{0x00, 0x1B, 0x21, 0x0A, 0x0F, 0xFF}},
+		{name: "negative_three_digit_group", addr: "8c:8e:c4:fff:9e:0a", wantErr: true},
+		{name: "negative_leading_zero_three_digits", addr: "8c:8e:c4:0ff:9e:0a", wantErr: true},
+		{name: "negative_empty_group", addr: "8c:8e::ff:9e:0a", wantErr: true},
+		{name: "negative_not_hex", addr: "8c:8e:c4:fg:9e:0a", wantErr: true},
+		{name: "negative_five_groups", addr: "8c:8e:c4:ff:9e", wantErr: true},
+		{name: "negative_seven_groups", addr: "8c:8e:c4:ff:9e:0a:01", wantErr: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			h, err := ParseHardwareAddr(tc.addr)
+			assert.Equal(t, tc.wantErr, err != nil)
+			assert.Equal(t, tc.wantAddr, h)
+		})
+	}
+}
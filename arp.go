@@ -0,0 +1,100 @@
+// Copyright (c) 2022 0x9ef. All rights reserved.
+// Use of this source code is governed by an MIT license
+// that can be found in the LICENSE file.
+package ethernet
+
+import (
+	"encoding/binary"
+	"errors"
+	"io"
+)
+
+// ErrUnsupportedARP is returned when ARP packet isn't an Ethernet/IPv4 ARP packet.
+var ErrUnsupportedARP = errors.New("only Ethernet/IPv4 ARP is supported")
+
+const (
+	ARPRequest uint16 = 1
+	ARPReply   uint16 = 2
+)
+
+// arpSize is the size of Ethernet/IPv4 ARP packet.
+const arpSize = 28
+
+// The Address Resolution Protocol (ARP) is a communication protocol used for discovering
+// the link layer address, such as a MAC address, associated with a given internet layer address,
+// typically an IPv4 address. Only Ethernet (HTYPE 1) with IPv4 (PTYPE 0x0800) is supported.
+//
+// https://datatracker.ietf.org/doc/html/rfc826
+type ARP struct {
+	HardwareType       uint16
+	ProtocolType       EtherType
+	HardwareLen        uint8
+	ProtocolLen        uint8
+	Operation          uint16
+	SenderHardwareAddr HardwareAddr
+	SenderProtocolAddr [4]byte
+	TargetHardwareAddr HardwareAddr
+	TargetProtocolAddr [4]byte
+}
+
+// ParseARP decodes Ethernet/IPv4 ARP packet from b, trailing bytes (padding) are ignored.
+func ParseARP(b []byte) (*ARP, error) {
+	if len(b) < arpSize {
+		return nil, io.ErrUnexpectedEOF
+	}
+	a := &ARP{
+		HardwareType: binary.BigEndian.Uint16(b[0:2]),
+		ProtocolType: EtherType(binary.BigEndian.Uint16(b[2:4])),
+		HardwareLen:  b[4],
+		ProtocolLen:  b[5],
+		Operation:    binary.BigEndian.Uint16(b[6:8]),
+	}
+	if a.HardwareType != 1 || a.ProtocolType != EtherTypeIPv4 || a.HardwareLen != 6 || a.ProtocolLen != 4 {
+		return nil, ErrUnsupportedARP
+	}
+	copy(a.SenderHardwareAddr[:], b[8:14])
+	copy(a.SenderProtocolAddr[:], b[14:18])
+	copy(a.TargetHardwareAddr[:], b[18:24])
+	copy(a.TargetProtocolAddr[:], b[24:28])
+	return a, nil
+}
+
+// Marshal serializes ARP packet into the byte representation.
+func (a *ARP) Marshal() []byte {
+	b := make([]byte, arpSize)
+	binary.BigEndian.PutUint16(b[0:2], a.HardwareType)
+	binary.BigEndian.PutUint16(b[2:4], uint16(a.ProtocolType))
+	b[4] = a.HardwareLen
+	b[5] = a.ProtocolLen
+	binary.BigEndian.PutUint16(b[6:8], a.Operation)
+	copy(b[8:14], a.SenderHardwareAddr[:])
+	copy(b[14:18], a.SenderProtocolAddr[:])
+	copy(b[18:24], a.TargetHardwareAddr[:])
+	copy(b[24:28], a.TargetProtocolAddr[:])
+	return b
+}
+
+// SetRequest turns the packet into Ethernet/IPv4 ARP request for targetIP. The target
+// hardware address is unknown, so it's set to zeroes, while the Ethernet frame carrying
+// the request is sent to the broadcast address (see NewARPRequestFrame).
+// The sender addresses are left unchanged.
+func (a *ARP) SetRequest(targetIP [4]byte) {
+	a.HardwareType = 1
+	a.ProtocolType = EtherTypeIPv4
+	a.HardwareLen = 6
+	a.ProtocolLen = 4
+	a.Operation = ARPRequest
+	a.TargetHardwareAddr = HardwareAddr{}
+	a.TargetProtocolAddr = targetIP
+}
+
+// NewARPRequestFrame returns a broadcast frame carrying ARP request from src/senderIP,
+// which asks for the hardware address of targetIP.
+func NewARPRequestFrame(src HardwareAddr, senderIP, targetIP [4]byte, opts ...FrameOption) *Frame {
+	a := &ARP{
+		SenderHardwareAddr: src,
+		SenderProtocolAddr: senderIP,
+	}
+	a.SetRequest(targetIP)
+	return NewBroadcastFrame(src, EtherTypeARP, a.Marshal(), opts...)
+}
@@ -0,0 +1,134 @@
+// Copyright (c) 2022 0x9ef. All rights reserved.
+// Use of this source code is governed by an MIT license
+// that can be found in the LICENSE file.
+package ethernet
+
+import (
+	"encoding/binary"
+	"errors"
+	"io"
+)
+
+// ErrSSIDTooLong is returned when the SSID exceeds 32 bytes.
+var ErrSSIDTooLong = errors.New("SSID exceeds 32 bytes")
+
+// beaconFixedSize is Timestamp (8) + Beacon Interval (2) + Capability Information (2).
+const beaconFixedSize = 12
+
+// maxSSIDLen is the maximum length of the SSID element data.
+const maxSSIDLen = 32
+
+// beaconRates is the Supported Rates element data of the beacons built by NewBeacon:
+// 1, 2, 5.5, 11 Mbit/s (basic) and 6, 9, 12, 18 Mbit/s in units of 500 kbit/s.
+var beaconRates = []byte{0x82, 0x84, 0x8B, 0x96, 0x0C, 0x12, 0x18, 0x24}
+
+// BeaconBody is the body of beacon and probe response frames: the fixed
+// fields followed by the information elements. The fixed fields are
+// little-endian on the wire.
+type BeaconBody struct {
+	Timestamp    uint64 // TSF timer in microseconds
+	Interval     uint16 // beacon interval in time units (1024 microseconds)
+	Capabilities uint16 // see ParseCapabilities
+	Elements     []InfoElement
+}
+
+// ParseBeaconBody decodes the body of beacon or probe response frame,
+// the elements data alias b.
+func ParseBeaconBody(b []byte) (*BeaconBody, error) {
+	if len(b) < beaconFixedSize {
+		return nil, io.ErrUnexpectedEOF
+	}
+	elems, err := ParseInfoElements(b[beaconFixedSize:])
+	if err != nil {
+		return nil, err
+	}
+	return &BeaconBody{
+		Timestamp:    binary.LittleEndian.Uint64(b[0:8]),
+		Interval:     binary.LittleEndian.Uint16(b[8:10]),
+		Capabilities: binary.LittleEndian.Uint16(b[10:12]),
+		Elements:     elems,
+	}, nil
+}
+
+// Marshal serializes the body into the byte representation.
+// Returns ErrElementTooLong if data of any element exceeds 255 bytes.
+func (b *BeaconBody) Marshal() ([]byte, error) {
+	elems, err := EncodeInfoElements(b.Elements)
+	if err != nil {
+		return nil, err
+	}
+	buf := make([]byte, beaconFixedSize, beaconFixedSize+len(elems))
+	binary.LittleEndian.PutUint64(buf[0:8], b.Timestamp)
+	binary.LittleEndian.PutUint16(buf[8:10], b.Interval)
+	binary.LittleEndian.PutUint16(buf[10:12], b.Capabilities)
+	return append(buf, elems...), nil
+}
+
+// Element returns the first element with the id.
+func (b *BeaconBody) Element(id uint8) (InfoElement, bool) {
+	for _, e := range b.Elements {
+		if e.ID == id {
+			return e, true
+		}
+	}
+	return InfoElement{}, false
+}
+
+// Channel returns the operating channel from the DS Parameter Set element, or if it's
+// absent (e.g. 5 GHz beacons), the primary channel from the HT Operation element.
+// Returns false if neither element is present.
+func (b *BeaconBody) Channel() (uint8, bool) {
+	if e, ok := b.Element(ElementDSParameterSet); ok && len(e.Data) >= 1 {
+		return e.Data[0], true
+	}
+	if e, ok := b.Element(ElementHTOperation); ok && len(e.Data) >= 1 {
+		return e.Data[0], true
+	}
+	return 0, false
+}
+
+// SSID returns the network name from the SSID element, or false if it's absent.
+// The empty SSID is used by the hidden networks.
+func (b *BeaconBody) SSID() (string, bool) {
+	e, ok := b.Element(ElementSSID)
+	if !ok {
+		return "", false
+	}
+	return string(e.Data), true
+}
+
+// NewBeaconE returns a new beacon sent by the AP src of the BSS bssid to the broadcast
+// address. The body carries the beacon interval (in time units of 1024 microseconds),
+// the ESS capability and the SSID, Supported Rates and DS Parameter Set elements.
+// The timestamp and the sequence number are left zero: the timestamp is set by
+// the hardware on transmission, the sequence number by the caller with SetSC.
+// Returns ErrSSIDTooLong if the SSID exceeds 32 bytes.
+func NewBeaconE(bssid, src HardwareAddr, ssid string, channel uint8, interval uint16) (*Frame80211, error) {
+	if len(ssid) > maxSSIDLen {
+		return nil, ErrSSIDTooLong
+	}
+	body := &BeaconBody{
+		Interval:     interval,
+		Capabilities: 1, // ESS
+		Elements: []InfoElement{
+			{ID: ElementSSID, Data: []byte(ssid)},
+			{ID: ElementSupportedRates, Data: beaconRates},
+			{ID: ElementDSParameterSet, Data: []byte{channel}},
+		},
+	}
+	payload, err := body.Marshal()
+	if err != nil {
+		return nil, err
+	}
+	fc := Encode80211Fc(0, uint16(Management), SubtypeBeacon, 0, 0, 0, 0, 0, 0, 0, 0)
+	return NewFrame80211E(BroadcastAddr, src, bssid, nil, fc, 0, payload)
+}
+
+// NewBeacon is like NewBeaconE, but panics if the beacon cannot be constructed.
+func NewBeacon(bssid, src HardwareAddr, ssid string, channel uint8, interval uint16) *Frame80211 {
+	f, err := NewBeaconE(bssid, src, ssid, channel, interval)
+	if err != nil {
+		panic(err)
+	}
+	return f
+}
@@ -0,0 +1,129 @@
+package ethernet
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBeaconBodyChannel(t *testing.T) {
+	fixed := []byte{
+		0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07, 0x08, // timestamp
+		0x64, 0x00, // interval 100 TU
+		0x31, 0x04, // capabilities
+	}
+	ssid := InfoElement{ID: ElementSSID, Data: []byte("test-ap")}
+	ds := InfoElement{ID: ElementDSParameterSet, Data: []byte{6}}
+	ht := InfoElement{ID: ElementHTOperation, Data: append([]byte{36}, make([]byte, 21)...)}
+
+	type suite struct {
+		name        string
+		elems       []InfoElement
+		wantChannel uint8
+		wantOk      bool
+	}
+
+	testCases := []suite{
+		{name: "positive_ds_param", elems: []InfoElement{ssid, ds}, wantChannel: 6, wantOk: true},
+		{name: "positive_ds_param_before_ht", elems: []InfoElement{ssid, ht, ds}, wantChannel: 6, wantOk: true},
+		{name: "positive_ht_operation", elems: []InfoElement{ssid, ht}, wantChannel: 36, wantOk: true},
+		{name: "negative_no_channel", elems: []InfoElement{ssid}},
+		{name: "negative_empty_ds_param", elems: []InfoElement{{ID: ElementDSParameterSet}}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			elems, err := EncodeInfoElements(tc.elems)
+			assert.NoError(t, err)
+			body, err := ParseBeaconBody(append(append([]byte(nil), fixed...), elems...))
+			if !assert.NoError(t, err) {
+				return
+			}
+			assert.Equal(t, uint64(0x0807060504030201), body.Timestamp)
+			assert.Equal(t, uint16(100), body.Interval)
+			assert.True(t, ParseCapabilities(body.Capabilities).Privacy)
+
+			ch, ok := body.Channel()
+			assert.Equal(t, tc.wantOk, ok)
+			assert.Equal(t, tc.wantChannel, ch)
+		})
+	}
+
+	_, err := ParseBeaconBody(fixed[:beaconFixedSize-1])
+	assert.Equal(t, io.ErrUnexpectedEOF, err)
+}
+
+func TestNewBeacon(t *testing.T) {
+	bssid := HardwareAddr{0x00, 0x1B, 0x21, 0x6D, 0x7E, 0x8F}
+	src := bssid
+
+	type suite struct {
+		name    string
+		ssid    string
+		channel uint8
+		wantErr error
+	}
+
+	testCases := []suite{
+		{name: "positive_beacon", ssid: "test-ap", channel: 6},
+		{name: "positive_hidden_ssid", ssid: "", channel: 11},
+		{name: "positive_max_ssid", ssid: strings.Repeat("A", maxSSIDLen), channel: 1},
+		{name: "negative_ssid_too_long", ssid: strings.Repeat("A", maxSSIDLen+1), wantErr: ErrSSIDTooLong},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			f, err := NewBeaconE(bssid, src, tc.ssid, tc.channel, 100)
+			assert.Equal(t, tc.wantErr, err)
+			if tc.wantErr != nil {
+				assert.Panics(t, func() { NewBeacon(bssid, src, tc.ssid, tc.channel, 100) })
+				return
+			}
+
+			decoded, err := Unmarshal80211(f.Marshal())
+			if !assert.NoError(t, err) {
+				return
+			}
+			assert.Equal(t, Management, decoded.Type())
+			assert.Equal(t, uint16(SubtypeBeacon), decoded.Subtype())
+			assert.Equal(t, BroadcastAddr, decoded.Receiver())
+			assert.Equal(t, src, decoded.Transmitter())
+			assert.Zero(t, decoded.SC())
+
+			body, err := ParseBeaconBody(decoded.Payload())
+			if !assert.NoError(t, err) {
+				return
+			}
+			assert.Equal(t, uint16(100), body.Interval)
+			assert.True(t, ParseCapabilities(body.Capabilities).ESS)
+			ssid, ok := body.SSID()
+			assert.True(t, ok)
+			assert.Equal(t, tc.ssid, ssid)
+			ch, ok := body.Channel()
+			assert.True(t, ok)
+			assert.Equal(t, tc.channel, ch)
+			rates, ok := body.Element(ElementSupportedRates)
+			assert.True(t, ok)
+			assert.Equal(t, beaconRates, rates.Data)
+		})
+	}
+}
+
+func TestNewBeaconSequenceNumber(t *testing.T) {
+	bssid := HardwareAddr{0x00, 0x1B, 0x21, 0x6D, 0x7E, 0x8F}
+	f := NewBeacon(bssid, bssid, "test-ap", 6, 100)
+	f.SetSC(Encode80211Sc(0, 42))
+
+	decoded, err := Unmarshal80211(f.Marshal())
+	if !assert.NoError(t, err) {
+		return
+	}
+	_, sn := Decode80211Sc(decoded.SC())
+	assert.Equal(t, uint16(42), sn)
+	body, err := ParseBeaconBody(decoded.Payload())
+	assert.NoError(t, err)
+	ssid, _ := body.SSID()
+	assert.Equal(t, "test-ap", ssid)
+}
@@ -0,0 +1,234 @@
+// Copyright (c) 2022 0x9ef. All rights reserved.
+// Use of this source code is governed by an MIT license
+// that can be found in the LICENSE file.
+package ethernet
+
+import (
+	"encoding/binary"
+	"errors"
+	"io"
+	"sync"
+	"time"
+)
+
+var (
+	// ErrBadChunk is returned when the chunk header is inconsistent.
+	ErrBadChunk = errors.New("malformed payload chunk header")
+	// ErrDuplicateChunk is returned when the chunk of the pending payload was already received.
+	ErrDuplicateChunk = errors.New("duplicate payload chunk")
+)
+
+// The chunk header prepended to the payload of each frame built by SplitPayload:
+//
+//	seq    uint16 index of the chunk, from 0
+//	total  uint16 number of chunks
+//	length uint16 number of data bytes following the header,
+//	              so the padding of the frame is not taken as data
+//
+// All fields are big-endian.
+const chunkHeaderSize = 6
+
+// maxChunks is the maximum number of chunks, limited by the width of total.
+const maxChunks = 0xFFFF
+
+const (
+	// DefaultReassemblyTimeout is the Timeout of PayloadReassembler if it's not set.
+	DefaultReassemblyTimeout = 30 * time.Second
+	// DefaultMaxPending is the MaxPending of PayloadReassembler if it's not set.
+	DefaultMaxPending = 64
+)
+
+// SplitPayload splits the payload into frames with the payload of at most mtu bytes each
+// (including the chunk header), so a buffer larger than MTU can be sent over the link.
+// It's an application level chunking, not IP fragmentation: each chunk is prefixed with
+// the sequence number and the number of chunks. If mtu <= 0 then DefaultMTU is used.
+// The chunks are copied, so the frames don't alias the payload.
+// SplitPayload panics if mtu doesn't leave room for the data, or the payload needs more
+// than 65535 chunks.
+func SplitPayload(src, dst HardwareAddr, etherType EtherType, payload []byte, mtu int) []*Frame {
+	if mtu <= 0 {
+		mtu = DefaultMTU
+	}
+	size := mtu - chunkHeaderSize
+	if size <= 0 {
+		panic("ethernet: mtu is too small for the chunk header")
+	}
+	total := (len(payload) + size - 1) / size
+	if total == 0 {
+		total = 1
+	}
+	if total > maxChunks {
+		panic("ethernet: payload needs too many chunks")
+	}
+
+	frames := make([]*Frame, 0, total)
+	for seq := 0; seq < total; seq++ {
+		data := payload[seq*size:]
+		if len(data) > size {
+			data = data[:size]
+		}
+		b := make([]byte, chunkHeaderSize, chunkHeaderSize+len(data))
+		binary.BigEndian.PutUint16(b[0:2], uint16(seq))
+		binary.BigEndian.PutUint16(b[2:4], uint16(total))
+		binary.BigEndian.PutUint16(b[4:6], uint16(len(data)))
+		frames = append(frames, NewFrame(src, dst, etherType, append(b, data...)))
+	}
+	return frames
+}
+
+// parseChunk decodes the chunk header from the frame payload and returns the chunk data.
+func parseChunk(payload []byte) (seq, total int, data []byte, err error) {
+	if len(payload) < chunkHeaderSize {
+		return 0, 0, nil, io.ErrUnexpectedEOF
+	}
+	seq = int(binary.BigEndian.Uint16(payload[0:2]))
+	total = int(binary.BigEndian.Uint16(payload[2:4]))
+	n := int(binary.BigEndian.Uint16(payload[4:6]))
+	if total == 0 || seq >= total {
+		return 0, 0, nil, ErrBadChunk
+	}
+	if len(payload) < chunkHeaderSize+n {
+		return 0, 0, nil, io.ErrUnexpectedEOF
+	}
+	return seq, total, payload[chunkHeaderSize : chunkHeaderSize+n], nil
+}
+
+// reassemblyKey identifies the payload being reassembled, chunks of different
+// payloads sent between the same stations with the same EtherType are not
+// distinguished, so they must not be interleaved.
+type reassemblyKey struct {
+	src, dst  HardwareAddr
+	etherType EtherType
+}
+
+type reassembly struct {
+	total   int
+	chunks  map[int][]byte // by seq, allocated as received, so a forged total costs nothing
+	size    int
+	started time.Time
+}
+
+// PayloadReassembler reassembles the payloads split by SplitPayload, the chunk frames
+// are accepted in any order. The zero value is ready to use, it's safe for concurrent use.
+type PayloadReassembler struct {
+	// Timeout is the maximum time from the first received chunk to the last one,
+	// incomplete payloads older than Timeout are dropped. If 0, DefaultReassemblyTimeout
+	// is used, a negative Timeout keeps the incomplete payloads until they are completed.
+	Timeout time.Duration
+	// MaxPending is the maximum number of incomplete payloads, the oldest one is dropped
+	// to start a new payload above the limit. If 0, DefaultMaxPending is used.
+	MaxPending int
+
+	mu      sync.Mutex
+	pending map[reassemblyKey]*reassembly
+	now     func() time.Time // time source, time.Now if nil
+}
+
+func (r *PayloadReassembler) clock() time.Time {
+	if r.now != nil {
+		return r.now()
+	}
+	return time.Now()
+}
+
+// Push adds the chunk frame, and returns the reassembled payload when all the chunks
+// are received. The chunk data is copied, so the frame can be reused after Push.
+// A chunk with a different number of chunks than the pending payload starts a new payload,
+// the pending one is dropped, e.g. the sender restarted with another MTU. Returns ErrDuplicateChunk if the chunk was already received,
+// and ErrBadChunk or io.ErrUnexpectedEOF if the chunk header is malformed.
+func (r *PayloadReassembler) Push(f *Frame) (payload []byte, complete bool, err error) {
+	seq, total, data, err := parseChunk(f.Payload())
+	if err != nil {
+		return nil, false, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	now := r.clock()
+	r.expire(now)
+
+	if total == 1 {
+		return append([]byte(nil), data...), true, nil
+	}
+	if r.pending == nil {
+		r.pending = make(map[reassemblyKey]*reassembly)
+	}
+	key := reassemblyKey{src: f.Source(), dst: f.Destination(), etherType: f.EtherType()}
+	ra, ok := r.pending[key]
+	if !ok || ra.total != total {
+		if !ok {
+			r.evict()
+		}
+		ra = &reassembly{total: total, chunks: make(map[int][]byte), started: now}
+		r.pending[key] = ra
+	}
+	if _, ok := ra.chunks[seq]; ok {
+		return nil, false, ErrDuplicateChunk
+	}
+	ra.chunks[seq] = append(make([]byte, 0, len(data)), data...)
+	ra.size += len(data)
+	if len(ra.chunks) < total {
+		return nil, false, nil
+	}
+
+	delete(r.pending, key)
+	payload = make([]byte, 0, ra.size)
+	for i := 0; i < total; i++ {
+		payload = append(payload, ra.chunks[i]...)
+	}
+	return payload, true, nil
+}
+
+// evict drops the oldest incomplete payload if there is no room for a new one.
+func (r *PayloadReassembler) evict() {
+	max := r.MaxPending
+	if max <= 0 {
+		max = DefaultMaxPending
+	}
+	if len(r.pending) < max {
+		return
+	}
+	var oldest reassemblyKey
+	var started time.Time
+	for key, ra := range r.pending {
+		if started.IsZero() || ra.started.Before(started) {
+			oldest, started = key, ra.started
+		}
+	}
+	delete(r.pending, oldest)
+}
+
+// Expire drops the incomplete payloads older than Timeout, and returns the number of
+// dropped payloads. Push expires the payloads as well, so calling Expire is needed
+// only to release the memory when no more chunks are pushed.
+func (r *PayloadReassembler) Expire() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.expire(r.clock())
+}
+
+func (r *PayloadReassembler) expire(now time.Time) int {
+	timeout := r.Timeout
+	if timeout == 0 {
+		timeout = DefaultReassemblyTimeout
+	}
+	if timeout < 0 {
+		return 0
+	}
+	var n int
+	deadline := now.Add(-timeout)
+	for key, ra := range r.pending {
+		if ra.started.Before(deadline) {
+			delete(r.pending, key)
+			n++
+		}
+	}
+	return n
+}
+
+// Pending returns the number of incomplete payloads.
+func (r *PayloadReassembler) Pending() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.pending)
+}
@@ -0,0 +1,214 @@
+package ethernet
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSplitPayload(t *testing.T) {
+	src := HardwareAddr{127, 127, 127, 50, 50, 50}
+	dst := HardwareAddr{255, 255, 255, 50, 50, 50}
+
+	type suite struct {
+		name       string
+		payload    []byte
+		mtu        int
+		wantFrames int
+	}
+
+	testCases := []suite{
+		{name: "positive_multiple_chunks", payload: generatePayload(), mtu: 300, wantFrames: 4},
+		{name: "positive_exact_chunks", payload: bytes.Repeat([]byte{1}, 2*(300-chunkHeaderSize)), mtu: 300, wantFrames: 2},
+		{name: "positive_short_padded", payload: []byte("HELLO"), mtu: 300, wantFrames: 1},
+		{name: "positive_default_mtu", payload: make([]byte, 4000), mtu: 0, wantFrames: 3},
+		{name: "positive_empty", payload: nil, mtu: 300, wantFrames: 1},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			frames := SplitPayload(src, dst, EtherTypeIPv4, tc.payload, tc.mtu)
+			assert.Len(t, frames, tc.wantFrames)
+
+			mtu := tc.mtu
+			if mtu <= 0 {
+				mtu = DefaultMTU
+			}
+			var got []byte
+			for i, f := range frames {
+				assert.True(t, f.FitsMTU(mtu))
+				assert.Equal(t, src, f.Source())
+				assert.Equal(t, EtherTypeIPv4, f.EtherType())
+
+				var decoded Frame
+				assert.NoError(t, Unmarshal(append([]byte(nil), f.Marshal()...), &decoded))
+				seq, total, data, err := parseChunk(decoded.Payload())
+				assert.NoError(t, err)
+				assert.Equal(t, i, seq)
+				assert.Equal(t, len(frames), total)
+				got = append(got, data...)
+			}
+			assert.Equal(t, len(tc.payload), len(got))
+			assert.True(t, bytes.Equal(tc.payload, got), "reassembled payload must match")
+		})
+	}
+
+	assert.Panics(t, func() { SplitPayload(src, dst, EtherTypeIPv4, []byte("HELLO"), chunkHeaderSize) })
+}
+
+func TestPayloadReassembler(t *testing.T) {
+	src := HardwareAddr{127, 127, 127, 50, 50, 50}
+	dst := HardwareAddr{255, 255, 255, 50, 50, 50}
+	payload := generatePayload()
+
+	type suite struct {
+		name         string
+		order        []int // indexes of the chunks in the push order
+		wantComplete bool
+		wantErr      error // error of the last push
+		wantPending  int
+	}
+
+	testCases := []suite{
+		{name: "positive_in_order", order: []int{0, 1, 2, 3}, wantComplete: true},
+		{name: "positive_out_of_order", order: []int{3, 1, 0, 2}, wantComplete: true},
+		{name: "negative_missing", order: []int{0, 1, 3}, wantPending: 1},
+		{name: "negative_duplicate", order: []int{2, 0, 2}, wantErr: ErrDuplicateChunk, wantPending: 1},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			frames := SplitPayload(src, dst, EtherTypeIPv4, payload, 300)
+			assert.Len(t, frames, 4)
+
+			var r PayloadReassembler
+			var got []byte
+			var complete bool
+			var err error
+			for i, idx := range tc.order {
+				got, complete, err = r.Push(frames[idx])
+				if i < len(tc.order)-1 {
+					assert.NoError(t, err)
+					assert.False(t, complete)
+				}
+			}
+			assert.Equal(t, tc.wantErr, err)
+			assert.Equal(t, tc.wantComplete, complete)
+			if tc.wantComplete {
+				assert.True(t, bytes.Equal(payload, got))
+			} else {
+				assert.Nil(t, got)
+			}
+			assert.Equal(t, tc.wantPending, r.Pending())
+		})
+	}
+}
+
+func TestPayloadReassemblerTimeout(t *testing.T) {
+	src := HardwareAddr{127, 127, 127, 50, 50, 50}
+	dst := HardwareAddr{255, 255, 255, 50, 50, 50}
+	frames := SplitPayload(src, dst, EtherTypeIPv4, generatePayload(), 300)
+
+	now := time.Unix(1000, 0)
+	r := &PayloadReassembler{Timeout: time.Second, now: func() time.Time { return now }}
+
+	// the chunk arrives after the payload has expired, so the payload is started again
+	_, _, err := r.Push(frames[0])
+	assert.NoError(t, err)
+	now = now.Add(2 * time.Second)
+	for _, f := range frames[1:] {
+		_, complete, err := r.Push(f)
+		assert.NoError(t, err)
+		assert.False(t, complete)
+	}
+	assert.Equal(t, 1, r.Pending())
+
+	now = now.Add(2 * time.Second)
+	assert.Equal(t, 1, r.Expire())
+	assert.Equal(t, 0, r.Pending())
+
+	// malformed chunk
+	_, _, err = r.Push(NewFrame(src, dst, EtherTypeIPv4, []byte{0, 1, 0, 1, 0, 0}))
+	assert.Equal(t, ErrBadChunk, err)
+}
+
+func TestPayloadReassemblerTotalMismatch(t *testing.T) {
+	src := HardwareAddr{127, 127, 127, 50, 50, 50}
+	dst := HardwareAddr{255, 255, 255, 50, 50, 50}
+	payload := generatePayload()
+	first := SplitPayload(src, dst, EtherTypeIPv4, payload, 300)
+	second := SplitPayload(src, dst, EtherTypeIPv4, payload, 200)
+	assert.NotEqual(t, len(first), len(second))
+
+	var r PayloadReassembler
+	for _, f := range first[:2] {
+		_, _, err := r.Push(f)
+		assert.NoError(t, err)
+	}
+
+	// the sender restarts with another MTU, the pending chunks are dropped
+	var got []byte
+	var complete bool
+	for _, f := range second {
+		var err error
+		got, complete, err = r.Push(f)
+		assert.NoError(t, err)
+	}
+	assert.True(t, complete)
+	assert.True(t, bytes.Equal(payload, got))
+	assert.Equal(t, 0, r.Pending())
+
+	for _, f := range first[2:] {
+		_, complete, err := r.Push(f)
+		assert.NoError(t, err)
+		assert.False(t, complete)
+	}
+	assert.Equal(t, 1, r.Pending())
+}
+
+func TestPayloadReassemblerLimits(t *testing.T) {
+	dst := HardwareAddr{255, 255, 255, 50, 50, 50}
+	// the first of 65535 chunks
+	forged := []byte{0x00, 0x00, 0xFF, 0xFF, 0x00, 0x01, 'A'}
+
+	now := time.Unix(1000, 0)
+	r := &PayloadReassembler{now: func() time.Time { return now }}
+	for i := 0; i < 2*DefaultMaxPending; i++ {
+		now = now.Add(time.Millisecond)
+		src := HardwareAddr{127, 127, 127, 50, byte(i >> 8), byte(i)}
+		_, complete, err := r.Push(NewFrame(src, dst, EtherTypeIPv4, forged))
+		assert.NoError(t, err)
+		assert.False(t, complete)
+	}
+	assert.Equal(t, DefaultMaxPending, r.Pending())
+
+	// the default timeout drops the incomplete payloads
+	now = now.Add(DefaultReassemblyTimeout + time.Second)
+	assert.Equal(t, DefaultMaxPending, r.Expire())
+	assert.Equal(t, 0, r.Pending())
+
+	// the oldest payload is dropped above MaxPending
+	payload := generatePayload()
+	r = &PayloadReassembler{MaxPending: 2, Timeout: -1, now: func() time.Time { return now }}
+	oldest := SplitPayload(HardwareAddr{127, 127, 127, 50, 50, 1}, dst, EtherTypeIPv4, payload, 300)
+	_, _, err := r.Push(oldest[0])
+	assert.NoError(t, err)
+	for i := 2; i <= 3; i++ {
+		now = now.Add(time.Millisecond)
+		src := HardwareAddr{127, 127, 127, 50, 50, byte(i)}
+		_, _, err := r.Push(SplitPayload(src, dst, EtherTypeIPv4, payload, 300)[0])
+		assert.NoError(t, err)
+	}
+	assert.Equal(t, 2, r.Pending())
+	for _, f := range oldest[1:] {
+		_, complete, err := r.Push(f)
+		assert.NoError(t, err)
+		assert.False(t, complete)
+	}
+
+	// a negative Timeout keeps the incomplete payloads
+	now = now.Add(time.Hour)
+	assert.Equal(t, 0, r.Expire())
+}
@@ -0,0 +1,58 @@
+// Copyright (c) 2022 0x9ef. All rights reserved.
+// Use of this source code is governed by an MIT license
+// that can be found in the LICENSE file.
+package ethernet
+
+// PayloadKind is a kind of the decoded frame payload.
+type PayloadKind uint8
+
+const (
+	PayloadRaw  PayloadKind = iota // []byte
+	PayloadARP                     // *ARP
+	PayloadIPv4                    // *IPv4Packet
+	PayloadIPv6                    // *IPv6Packet
+)
+
+// IPv4Packet is IPv4 header with the packet payload.
+type IPv4Packet struct {
+	Header  *IPv4Header
+	Payload []byte
+}
+
+// IPv6Packet is IPv6 header with the packet payload.
+type IPv6Packet struct {
+	Header  *IPv6Header
+	Payload []byte
+}
+
+// DecodePayload decodes the frame payload based on the EtherType. Supported types:
+//
+//	EtherTypeARP  -> PayloadARP, *ARP
+//	EtherTypeIPv4 -> PayloadIPv4, *IPv4Packet
+//	EtherTypeIPv6 -> PayloadIPv6, *IPv6Packet
+//
+// For all other types returns PayloadRaw with the raw payload bytes.
+func (f *Frame) DecodePayload() (PayloadKind, interface{}, error) {
+	switch f.etherType {
+	case EtherTypeARP:
+		a, err := ParseARP(f.payload)
+		if err != nil {
+			return PayloadARP, nil, err
+		}
+		return PayloadARP, a, nil
+	case EtherTypeIPv4:
+		h, p, err := ParseIPv4(f.payload)
+		if err != nil {
+			return PayloadIPv4, nil, err
+		}
+		return PayloadIPv4, &IPv4Packet{Header: h, Payload: p}, nil
+	case EtherTypeIPv6:
+		h, p, err := ParseIPv6(f.payload)
+		if err != nil {
+			return PayloadIPv6, nil, err
+		}
+		return PayloadIPv6, &IPv6Packet{Header: h, Payload: p}, nil
+	default:
+		return PayloadRaw, f.payload, nil
+	}
+}
@@ -0,0 +1,211 @@
+package ethernet
+
+import (
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFrameDecodePayload(t *testing.T) {
+	src := HardwareAddr{127, 127, 127, 50, 50, 50}
+	dst := HardwareAddr{255, 255, 255, 50, 50, 50}
+
+	arp := &ARP{
+		HardwareType:       1,
+		ProtocolType:       EtherTypeIPv4,
+		HardwareLen:        6,
+		ProtocolLen:        4,
+		Operation:          ARPRequest,
+		SenderHardwareAddr: src,
+		SenderProtocolAddr: [4]byte{192, 168, 0, 1},
+		TargetProtocolAddr: [4]byte{192, 168, 0, 2},
+	}
+	ipv4 := []byte{
+		0x45, 0x00, 0x00, 0x19, 0x00, 0x01, 0x40, 0x00, 0x40, 0x11, 0x00, 0x00,
+		192, 168, 0, 1, 192, 168, 0, 2,
+		'H', 'E', 'L', 'L', 'O',
+	}
+	ipv6 := append([]byte{0x60, 0x00, 0x00, 0x01, 0x00, 0x05, 0x11, 0x40}, make([]byte, 32)...)
+	ipv6 = append(ipv6, 'H', 'E', 'L', 'L', 'O')
+
+	type suite struct {
+		name      string
+		etherType EtherType
+		payload   []byte
+		wantKind  PayloadKind
+		wantErr   error
+		check     func(t *testing.T, v interface{})
+	}
+
+	testCases := []suite{
+		{
+			name:      "positive_arp",
+			etherType: EtherTypeARP,
+			payload:   arp.Marshal(),
+			wantKind:  PayloadARP,
+			check: func(t *testing.T, v interface{}) {
+				assert.Equal(t, arp, v.(*ARP))
+			},
+		},
+		{
+			name:      "positive_ipv4",
+			etherType: EtherTypeIPv4,
+			payload:   ipv4,
+			wantKind:  PayloadIPv4,
+			check: func(t *testing.T, v interface{}) {
+				p := v.(*IPv4Packet)
+				assert.Equal(t, uint8(17), p.Header.Protocol)
+				assert.Equal(t, [4]byte{192, 168, 0, 2}, p.Header.Dst)
+				assert.Equal(t, []byte("HELLO"), p.Payload)
+			},
+		},
+		{
+			name:      "positive_ipv6",
+			etherType: EtherTypeIPv6,
+			payload:   ipv6,
+			wantKind:  PayloadIPv6,
+			check: func(t *testing.T, v interface{}) {
+				p := v.(*IPv6Packet)
+				assert.Equal(t, uint32(1), p.Header.FlowLabel)
+				assert.Equal(t, []byte("HELLO"), p.Payload)
+			},
+		},
+		{
+			name:      "positive_raw",
+			etherType: EtherType(0x88B5),
+			payload:   []byte("HELLO"),
+			wantKind:  PayloadRaw,
+			check: func(t *testing.T, v interface{}) {
+				assert.Equal(t, []byte("HELLO"), v.([]byte)[:5])
+			},
+		},
+		{
+			name:      "negative_ipv4_version",
+			etherType: EtherTypeIPv4,
+			payload:   ipv6,
+			wantKind:  PayloadIPv4,
+			wantErr:   ErrBadIPVersion,
+		},
+		{
+			name:      "negative_arp_truncated",
+			etherType: EtherTypeARP,
+			payload:   arp.Marshal()[:20],
+			wantKind:  PayloadARP,
+			wantErr:   io.ErrUnexpectedEOF,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			f := NewFrame(src, dst, tc.etherType, tc.payload, WithRawPayload())
+			kind, v, err := f.DecodePayload()
+			assert.Equal(t, tc.wantKind, kind)
+			if tc.wantErr != nil {
+				assert.Equal(t, tc.wantErr, err)
+				return
+			}
+			assert.NoError(t, err)
+			tc.check(t, v)
+		})
+	}
+}
+
+func TestFrameIPv6(t *testing.T) {
+	h := &IPv6Header{
+		Version:       6,
+		TrafficClass:  0xB8,
+		FlowLabel:     0xABCDE,
+		PayloadLength: 5,
+		NextHeader:    17,
+		HopLimit:      64,
+		Src:           [16]byte{0xFE, 0x80, 15: 1},
+		Dst:           [16]byte{0xFF, 0x02, 15: 1},
+	}
+	packet := append(h.Marshal(), 'H', 'E', 'L', 'L', 'O')
+	badVersion := append([]byte(nil), packet...)
+	badVersion[0] = 0x40
+	truncated := append([]byte(nil), packet...)
+	truncated[5] = 100 // payload length exceeds the frame payload
+
+	type suite struct {
+		name      string
+		etherType EtherType
+		payload   []byte
+		wantErr   error
+	}
+
+	testCases := []suite{
+		{
+			name:      "positive_round_trip",
+			etherType: EtherTypeIPv6,
+			payload:   packet,
+		},
+		{
+			name:      "negative_ethertype",
+			etherType: EtherTypeIPv4,
+			payload:   packet,
+			wantErr:   ErrEtherTypeMismatch,
+		},
+		{
+			name:      "negative_version",
+			etherType: EtherTypeIPv6,
+			payload:   badVersion,
+			wantErr:   ErrBadIPVersion,
+		},
+		{
+			name:      "negative_truncated",
+			etherType: EtherTypeIPv6,
+			payload:   truncated,
+			wantErr:   io.ErrUnexpectedEOF,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// the padding of short payload must not be returned
+			f := NewFrame(HardwareAddr{127, 127, 127, 50, 50, 50}, HardwareAddr{255, 255, 255, 50, 50, 50}, tc.etherType, tc.payload)
+			var decoded Frame
+			assert.NoError(t, Unmarshal(f.Marshal(), &decoded))
+
+			got, rest, err := decoded.IPv6()
+			assert.Equal(t, tc.wantErr, err)
+			if tc.wantErr != nil {
+				return
+			}
+			assert.Equal(t, h, got)
+			assert.Equal(t, []byte("HELLO"), rest)
+			assert.Equal(t, packet[:ipv6HeaderSize], got.Marshal())
+		})
+	}
+}
+
+func TestNewARPRequestFrame(t *testing.T) {
+	src := HardwareAddr{127, 127, 127, 50, 50, 50}
+	senderIP, targetIP := [4]byte{192, 168, 0, 1}, [4]byte{192, 168, 0, 2}
+
+	f := NewARPRequestFrame(src, senderIP, targetIP, WithVLAN(PcpBE, 0, 10))
+	assert.Equal(t, HardwareAddr{0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF}, f.Destination(), "request is broadcast on Ethernet")
+	assert.Equal(t, src, f.Source())
+	assert.Equal(t, EtherTypeARP, f.EtherType())
+	assert.NotNil(t, f.Tag8021Q())
+
+	var decoded Frame
+	assert.NoError(t, Unmarshal(f.Marshal(), &decoded))
+	kind, v, err := decoded.DecodePayload()
+	assert.NoError(t, err)
+	assert.Equal(t, PayloadARP, kind)
+	arp := v.(*ARP)
+	assert.Equal(t, ARPRequest, arp.Operation)
+	assert.Equal(t, HardwareAddr{}, arp.TargetHardwareAddr, "target hardware address is unknown")
+	assert.Equal(t, targetIP, arp.TargetProtocolAddr)
+	assert.Equal(t, src, arp.SenderHardwareAddr)
+	assert.Equal(t, senderIP, arp.SenderProtocolAddr)
+
+	// reply turned into request
+	reply := *arp
+	reply.Operation = ARPReply
+	reply.TargetHardwareAddr = HardwareAddr{1, 2, 3, 4, 5, 6}
+	reply.SetRequest(targetIP)
+	assert.Equal(t, *arp, reply)
+}
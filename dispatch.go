@@ -0,0 +1,54 @@
+// Copyright (c) 2022 0x9ef. All rights reserved.
+// Use of this source code is governed by an MIT license
+// that can be found in the LICENSE file.
+package ethernet
+
+import (
+	"errors"
+	"sync"
+)
+
+// ErrNoHandler is returned by Dispatch when there is no handler for the frame EtherType
+// and the default handler is not set.
+var ErrNoHandler = errors.New("no handler registered for EtherType")
+
+// Dispatcher invokes handlers registered for the frame EtherType.
+// It's safe to register handlers and dispatch frames concurrently.
+type Dispatcher struct {
+	mu       sync.RWMutex
+	handlers map[EtherType]func(*Frame) error
+	fallback func(*Frame) error
+}
+
+// Register registers the handler for the EtherType, replacing the previous one.
+func (d *Dispatcher) Register(et EtherType, h func(*Frame) error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.handlers == nil {
+		d.handlers = make(map[EtherType]func(*Frame) error)
+	}
+	d.handlers[et] = h
+}
+
+// RegisterDefault registers the handler for frames without a registered EtherType handler.
+func (d *Dispatcher) RegisterDefault(h func(*Frame) error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.fallback = h
+}
+
+// Dispatch invokes the handler registered for the frame EtherType, or the default handler.
+// Returns the handler error, or ErrNoHandler if there is no handler.
+func (d *Dispatcher) Dispatch(f *Frame) error {
+	d.mu.RLock()
+	h, ok := d.handlers[f.etherType]
+	if !ok {
+		h = d.fallback
+	}
+	d.mu.RUnlock()
+
+	if h == nil {
+		return ErrNoHandler
+	}
+	return h(f)
+}
@@ -0,0 +1,39 @@
+package ethernet
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDispatcher(t *testing.T) {
+	src := HardwareAddr{127, 127, 127, 50, 50, 50}
+	dst := HardwareAddr{255, 255, 255, 50, 50, 50}
+
+	var d Dispatcher
+	assert.Equal(t, ErrNoHandler, d.Dispatch(NewFrame(src, dst, EtherTypeIPv4, nil)))
+
+	calls := make(map[string]int)
+	errARP := errors.New("arp failed")
+	d.Register(EtherTypeIPv4, func(f *Frame) error {
+		calls["ipv4"]++
+		return nil
+	})
+	d.Register(EtherTypeARP, func(f *Frame) error {
+		calls["arp"]++
+		return errARP
+	})
+
+	assert.NoError(t, d.Dispatch(NewFrame(src, dst, EtherTypeIPv4, nil)))
+	assert.NoError(t, d.Dispatch(NewFrame(src, dst, EtherTypeIPv4, nil)))
+	assert.Equal(t, errARP, d.Dispatch(NewFrame(src, dst, EtherTypeARP, nil)))
+	assert.Equal(t, ErrNoHandler, d.Dispatch(NewFrame(src, dst, EtherTypeIPv6, nil)))
+
+	d.RegisterDefault(func(f *Frame) error {
+		calls["default"]++
+		return nil
+	})
+	assert.NoError(t, d.Dispatch(NewFrame(src, dst, EtherTypeIPv6, nil)))
+	assert.Equal(t, map[string]int{"ipv4": 2, "arp": 1, "default": 1}, calls)
+}
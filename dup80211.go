@@ -0,0 +1,29 @@
+// Copyright (c) 2022 0x9ef. All rights reserved.
+// Use of this source code is governed by an MIT license
+// that can be found in the LICENSE file.
+package ethernet
+
+// DupDetector80211 detects retransmitted duplicates of the received 802.11 frames.
+// It keeps the sequence control (sequence and fragment numbers) of the last frame
+// per transmitter address, a frame with the Retry bit set and the same sequence
+// control as the last one is a duplicate. QoS frames are not tracked per TID.
+// The zero value is ready to use, it's not safe for concurrent use.
+type DupDetector80211 struct {
+	last map[HardwareAddr]uint16
+}
+
+// IsDuplicate reports whether the frame is a retransmitted duplicate of the previous
+// frame of the transmitter, and records its sequence control. Control frames,
+// which carry no sequence control, are never duplicates.
+func (d *DupDetector80211) IsDuplicate(f *Frame80211) bool {
+	if f.Type() == Control {
+		return false
+	}
+	if d.last == nil {
+		d.last = make(map[HardwareAddr]uint16)
+	}
+	ta := f.Transmitter()
+	last, seen := d.last[ta]
+	d.last[ta] = f.sc
+	return seen && f.Retry() && last == f.sc
+}
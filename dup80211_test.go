@@ -0,0 +1,68 @@
+package ethernet
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDupDetector80211(t *testing.T) {
+	ta := HardwareAddr{127, 127, 127, 50, 50, 50}
+	other := HardwareAddr{255, 255, 255, 50, 50, 20}
+	fc := Encode80211Fc(0, uint16(Data), SubtypeData, 1, 0, 0, 0, 0, 0, 0, 0)
+	retryFc := Encode80211Fc(0, uint16(Data), SubtypeData, 1, 0, 0, 1, 0, 0, 0, 0)
+
+	newFrame := func(ta HardwareAddr, fc, sc uint16) *Frame80211 {
+		f := NewFrame80211(HardwareAddr{255, 255, 255, 50, 50, 50}, ta, HardwareAddr{}, nil, fc, 0, []byte("HELLO"))
+		f.SetSC(sc)
+		decoded, err := Unmarshal80211(f.Marshal())
+		assert.NoError(t, err)
+		return decoded
+	}
+
+	type suite struct {
+		name    string
+		frame   *Frame80211
+		wantDup bool
+	}
+
+	// cases are fed to the same detector in order
+	testCases := []suite{
+		{
+			name:  "positive_original",
+			frame: newFrame(ta, fc, Encode80211Sc(0, 100)),
+		},
+		{
+			name:    "positive_retried_copy",
+			frame:   newFrame(ta, retryFc, Encode80211Sc(0, 100)),
+			wantDup: true,
+		},
+		{
+			name:  "negative_other_transmitter",
+			frame: newFrame(other, retryFc, Encode80211Sc(0, 100)),
+		},
+		{
+			name:  "negative_next_fragment",
+			frame: newFrame(ta, retryFc, Encode80211Sc(1, 100)),
+		},
+		{
+			name:  "negative_same_sc_without_retry",
+			frame: newFrame(ta, fc, Encode80211Sc(1, 100)),
+		},
+		{
+			name:  "negative_next_sequence",
+			frame: newFrame(ta, retryFc, Encode80211Sc(0, 101)),
+		},
+		{
+			name:  "negative_control",
+			frame: NewFrame80211(ta, ta, HardwareAddr{}, nil, Encode80211Fc(0, uint16(Control), SubtypeRts, 0, 0, 0, 1, 0, 0, 0, 0), 0, nil),
+		},
+	}
+
+	var d DupDetector80211
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.wantDup, d.IsDuplicate(tc.frame))
+		})
+	}
+}
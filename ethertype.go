@@ -3,6 +3,11 @@
 // that can be found in the LICENSE file.
 package ethernet
 
+import (
+	"errors"
+	"sort"
+)
+
 // EtherType is a two-octet field in an Ethernet frame.
 // It is used to indicate which protocol is encapsulated in the payload
 // of the frame and is used at the receiving end by the data link layer to
@@ -13,7 +18,115 @@ package ethernet
 type EtherType uint16
 
 const (
-	EtherTypeIPv4 EtherType = 0x8000
+	EtherTypeIPv4 EtherType = 0x0800
+	EtherTypeARP  EtherType = 0x0806
 	EtherTypeIPv6 EtherType = 0x86DD
 	EtherTypeVlan EtherType = 0x8100
+	// IEEE 802.1ad service tag (S-Tag)
+	EtherTypeQinQ EtherType = 0x88A8
+	// Legacy (pre 802.1ad) QinQ tags
+	EtherTypeQinQ9100 EtherType = 0x9100
+	EtherTypeQinQ9200 EtherType = 0x9200
+	// Control protocols of the link, see Frame.IsControlPlane
+	EtherTypeSlow  EtherType = 0x8809 // Slow Protocols (LACP, Marker, OAM)
+	EtherTypeEAPOL EtherType = 0x888E // IEEE 802.1X port access control
+	EtherTypeLLDP  EtherType = 0x88CC // Link Layer Discovery Protocol
+	EtherTypeCFM   EtherType = 0x8902 // IEEE 802.1ag Connectivity Fault Management
 )
+
+// maxLength is the largest value of the EtherType field interpreted as the payload length (IEEE 802.3).
+const maxLength = 1500
+
+// IsLength reports whether the value is the payload length of IEEE 802.3 frame,
+// rather than EtherType of Ethernet II frame.
+func (e EtherType) IsLength() bool { return e <= maxLength }
+
+// etherTypeNames is the registry of known EtherTypes.
+var etherTypeNames = map[EtherType]string{
+	EtherTypeIPv4:     "IPv4",
+	EtherTypeARP:      "ARP",
+	EtherTypeIPv6:     "IPv6",
+	EtherTypeVlan:     "802.1Q",
+	EtherTypeQinQ:     "802.1ad",
+	EtherTypeQinQ9100: "QinQ 0x9100",
+	EtherTypeQinQ9200: "QinQ 0x9200",
+	EtherTypeSlow:     "Slow Protocols",
+	EtherTypeEAPOL:    "EAPOL",
+	EtherTypeLLDP:     "LLDP",
+	EtherTypeCFM:      "CFM",
+}
+
+// Name returns the name of the EtherType, or empty string if it's unknown.
+func (e EtherType) Name() string { return etherTypeNames[e] }
+
+// MinPayloadLen returns the minimal payload length of the protocol, which is the size
+// of the fixed header (ARP for Ethernet/IPv4). Payloads shorter than it are truncated,
+// so it can be used to reject them before parsing. Returns 0 for other EtherTypes.
+func (e EtherType) MinPayloadLen() int {
+	switch e {
+	case EtherTypeARP:
+		return arpSize
+	case EtherTypeIPv4:
+		return minIPv4HeaderSize
+	case EtherTypeIPv6:
+		return ipv6HeaderSize
+	default:
+		return 0
+	}
+}
+
+var (
+	// ErrPayloadTooShort is returned when the payload is shorter than the minimal length of its protocol.
+	ErrPayloadTooShort = errors.New("payload is shorter than the protocol header")
+	// ErrReservedVLAN is returned when the tag carries the VLAN identifier reserved by 802.1Q.
+	ErrReservedVLAN = errors.New("802.1Q VLAN identifier 4095 is reserved")
+)
+
+// reservedVlan is the VLAN identifier reserved by 802.1Q.
+const reservedVlan = 0xFFF
+
+// ValidateForEtherType checks that the payload is not shorter than MinPayloadLen of
+// the frame EtherType (ErrPayloadTooShort), and that the tags don't carry the
+// reserved VID 4095 (ErrReservedVLAN).
+func (f *Frame) ValidateForEtherType() error {
+	if f.tag8021q != nil {
+		if _, _, vid := Decode8021qTCI(f.tag8021q.TCI); vid == reservedVlan {
+			return ErrReservedVLAN
+		}
+	}
+	for _, tag := range f.inner {
+		if _, _, vid := Decode8021qTCI(tag.TCI); vid == reservedVlan {
+			return ErrReservedVLAN
+		}
+	}
+	if len(f.payload) < f.etherType.MinPayloadLen() {
+		return ErrPayloadTooShort
+	}
+	return nil
+}
+
+// KnownEtherTypes returns the known EtherTypes sorted in ascending order.
+func KnownEtherTypes() []EtherType {
+	known := make([]EtherType, 0, len(etherTypeNames))
+	for e := range etherTypeNames {
+		known = append(known, e)
+	}
+	sort.Slice(known, func(i, j int) bool { return known[i] < known[j] })
+	return known
+}
+
+// IsControlPlane reports whether the frame belongs to the link control protocols, which
+// a software switch punts to the CPU rather than forwards: the frame is sent to the
+// reserved bridge multicast address (STP, LLDP, etc, see IsBridgeReserved), or carries
+// one of the control EtherTypes (EtherTypeSlow, EtherTypeEAPOL, EtherTypeLLDP, EtherTypeCFM).
+func (f *Frame) IsControlPlane() bool {
+	if f.dst.IsBridgeReserved() {
+		return true
+	}
+	switch f.etherType {
+	case EtherTypeSlow, EtherTypeEAPOL, EtherTypeLLDP, EtherTypeCFM:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,170 @@
+package ethernet
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestKnownEtherTypes(t *testing.T) {
+	known := KnownEtherTypes()
+	assert.Contains(t, known, EtherTypeIPv4)
+	assert.Contains(t, known, EtherTypeIPv6)
+	assert.Contains(t, known, EtherTypeARP)
+	assert.Contains(t, known, EtherTypeVlan)
+	assert.True(t, sort.SliceIsSorted(known, func(i, j int) bool { return known[i] < known[j] }))
+	for _, e := range known {
+		assert.NotEmpty(t, e.Name())
+	}
+
+	// modifying the returned slice doesn't affect the registry
+	known[0] = 0
+	assert.NotEqual(t, EtherType(0), KnownEtherTypes()[0])
+}
+
+func TestEtherTypeName(t *testing.T) {
+	type suite struct {
+		name      string
+		etherType EtherType
+		wantName  string
+	}
+
+	testCases := []suite{
+		{
+			name:      "positive_ipv4",
+			etherType: EtherTypeIPv4,
+			wantName:  "IPv4",
+		},
+		{
+			name:      "positive_vlan",
+			etherType: EtherTypeVlan,
+			wantName:  "802.1Q",
+		},
+		{
+			name:      "negative_unknown",
+			etherType: 0x1234,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.wantName, tc.etherType.Name())
+		})
+	}
+}
+
+func TestEtherTypeIsLength(t *testing.T) {
+	assert.True(t, EtherType(0).IsLength())
+	assert.True(t, EtherType(1500).IsLength())
+	assert.False(t, EtherType(1536).IsLength())
+	assert.False(t, EtherTypeIPv4.IsLength())
+}
+
+func TestEtherTypeMinPayloadLen(t *testing.T) {
+	type suite struct {
+		name      string
+		etherType EtherType
+		wantLen   int
+	}
+
+	testCases := []suite{
+		{name: "positive_arp", etherType: EtherTypeARP, wantLen: 28},
+		{name: "positive_ipv4", etherType: EtherTypeIPv4, wantLen: 20},
+		{name: "positive_ipv6", etherType: EtherTypeIPv6, wantLen: 40},
+		{name: "negative_vlan", etherType: EtherTypeVlan},
+		{name: "negative_unknown", etherType: 0x1234},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.wantLen, tc.etherType.MinPayloadLen())
+		})
+	}
+}
+
+func TestFrameValidateForEtherType(t *testing.T) {
+	src := HardwareAddr{127, 127, 127, 50, 50, 50}
+	dst := HardwareAddr{255, 255, 255, 50, 50, 50}
+	arp := (&ARP{HardwareType: 1, ProtocolType: EtherTypeIPv4, HardwareLen: 6, ProtocolLen: 4, Operation: ARPRequest}).Marshal()
+	qinq := NewFrame(src, dst, EtherTypeARP, arp, WithVLAN(PcpBE, 0, 100))
+	qinq.SetInnerTags([]Tag8021Q{{TPID: uint16(EtherTypeVlan), TCI: Encode8021qTCI(PcpBE, 0, 4095)}})
+
+	type suite struct {
+		name    string
+		frame   *Frame
+		wantErr error
+	}
+
+	testCases := []suite{
+		{
+			name:  "positive_arp",
+			frame: NewFrame(src, dst, EtherTypeARP, arp, WithRawPayload()),
+		},
+		{
+			name:  "positive_unknown_ethertype",
+			frame: NewFrame(src, dst, 0x1234, nil, WithRawPayload()),
+		},
+		{
+			name:  "positive_padded_ipv4",
+			frame: NewFrame(src, dst, EtherTypeIPv4, []byte("HELLO")),
+		},
+		{
+			name:    "negative_truncated_arp",
+			frame:   NewFrame(src, dst, EtherTypeARP, arp[:20], WithRawPayload()),
+			wantErr: ErrPayloadTooShort,
+		},
+		{
+			name:    "negative_short_ipv6",
+			frame:   NewFrame(src, dst, EtherTypeIPv6, make([]byte, 39), WithRawPayload()),
+			wantErr: ErrPayloadTooShort,
+		},
+		{
+			name:    "negative_reserved_vid",
+			frame:   NewFrame(src, dst, EtherTypeARP, arp, WithVLAN(PcpBE, 0, 4095)),
+			wantErr: ErrReservedVLAN,
+		},
+		{
+			name:    "negative_reserved_inner_vid",
+			frame:   qinq,
+			wantErr: ErrReservedVLAN,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.wantErr, tc.frame.ValidateForEtherType())
+		})
+	}
+}
+
+func TestFrameIsControlPlane(t *testing.T) {
+	src := HardwareAddr{127, 127, 127, 50, 50, 50}
+	dst := HardwareAddr{0x00, 0x1B, 0x21, 0x6D, 0x7E, 0x8F}
+	lldp := HardwareAddr{0x01, 0x80, 0xC2, 0x00, 0x00, 0x0E}
+	stp := HardwareAddr{0x01, 0x80, 0xC2, 0x00, 0x00, 0x00}
+
+	type suite struct {
+		name      string
+		dst       HardwareAddr
+		etherType EtherType
+		want      bool
+	}
+
+	testCases := []suite{
+		{name: "positive_lldp", dst: lldp, etherType: EtherTypeLLDP, want: true},
+		{name: "positive_stp_8023", dst: stp, etherType: 38, want: true},
+		{name: "positive_lacp", dst: HardwareAddr{0x01, 0x80, 0xC2, 0x00, 0x00, 0x02}, etherType: EtherTypeSlow, want: true},
+		{name: "positive_eapol_unicast", dst: dst, etherType: EtherTypeEAPOL, want: true},
+		{name: "negative_data", dst: dst, etherType: EtherTypeIPv4},
+		{name: "negative_broadcast", dst: BroadcastAddr, etherType: EtherTypeARP},
+		{name: "negative_other_multicast", dst: HardwareAddr{0x01, 0x00, 0x5E, 0x00, 0x00, 0x01}, etherType: EtherTypeIPv4},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			f := NewFrame(src, tc.dst, tc.etherType, []byte("HELLO"))
+			assert.Equal(t, tc.want, f.IsControlPlane())
+		})
+	}
+}
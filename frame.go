@@ -4,8 +4,12 @@
 package ethernet
 
 import (
+	"bytes"
+	"crypto/sha256"
 	"encoding/binary"
+	"errors"
 	"fmt"
+	"hash"
 	"hash/crc32"
 	"io"
 	"strings"
@@ -28,27 +32,63 @@ type Frame struct {
 	dst       HardwareAddr // destination MAC address
 	src       HardwareAddr // source MAC address
 	tag8021q  *Tag8021Q    // 802.1Q (can be nil)
+	inner     []Tag8021Q   // stacked (QinQ) tags following the outer tag
 	etherType EtherType
 	payload   []byte
 	fcs       [4]byte
+	trailer   []byte // bytes following FCS (decoded frames only)
+	fcsMode   FCSMode
+	capLen    int    // captured length of the truncated frame (decoded frames only)
+	origLen   int    // original length of the truncated frame on the wire
+	padded    int    // padding bytes added by NewFrame plus one, 0 if unknown (e.g. decoded frames)
+	cacheOn   bool   // Marshal output is cached, see SetMarshalCache
+	cache     []byte // output of the last Marshal
+	dirty     bool   // the frame was modified since the cache was filled
 }
 
+// FCSMode controls how the FCS is produced when the frame is marshaled.
+type FCSMode uint8
+
+const (
+	// FCSCompute computes the FCS over the serialized frame (default).
+	FCSCompute FCSMode = iota
+	// FCSKeep writes the FCS set by SetFCS (or decoded by Unmarshal) verbatim,
+	// e.g. when it's computed by the external hardware or deliberately wrong.
+	FCSKeep
+)
+
 func (f *Frame) String() string {
 	var sb strings.Builder
 	sb.WriteString("dst=" + f.dst.String())
 	sb.WriteString(" src=" + f.src.String())
-	sb.WriteString(fmt.Sprintf(" etherType=%X", f.EtherType()))
+	if _, length, isLength := f.EtherTypeOrLength(); isLength {
+		// IEEE 802.3 frame, the protocol is identified by SNAP header if any
+		sb.WriteString(fmt.Sprintf(" len=%d", length))
+		if _, pid, _, err := DecodeSNAP(f.payload); err == nil {
+			sb.WriteString(fmt.Sprintf(" snap=%X", pid))
+		}
+	} else {
+		sb.WriteString(fmt.Sprintf(" etherType=%X", f.EtherType()))
+	}
 	if f.tag8021q != nil {
-		sb.WriteString(fmt.Sprintf(" vlan[tpid=0x%X", f.tag8021q.TPID))
-		pcp, dei, vlan := Decode8021qTCI(f.tag8021q.TCI)
-		sb.WriteString(fmt.Sprintf(" pcp=0x%X(%s)", uint16(pcp), pcp.String()))
-		sb.WriteString(fmt.Sprintf(" dei=0x%X", dei))
-		sb.WriteString(fmt.Sprintf(" vlan=0x%X]", vlan))
+		writeTag(&sb, *f.tag8021q)
+		for _, tag := range f.inner {
+			writeTag(&sb, tag)
+		}
 	}
 	sb.WriteString(fmt.Sprintf(" size=%d", f.Size()))
 	return sb.String()
 }
 
+// writeTag writes the tag description, tags are written in the wire order (outer first).
+func writeTag(sb *strings.Builder, tag Tag8021Q) {
+	sb.WriteString(fmt.Sprintf(" vlan[tpid=0x%X", tag.TPID))
+	pcp, dei, vlan := Decode8021qTCI(tag.TCI)
+	sb.WriteString(fmt.Sprintf(" pcp=0x%X(%s)", uint16(pcp), pcp.String()))
+	sb.WriteString(fmt.Sprintf(" dei=0x%X", dei))
+	sb.WriteString(fmt.Sprintf(" vlan=0x%X]", vlan))
+}
+
 // minHeaderSize is 6 bytes DST + 6 bytes SRC + 4 bytes FCS
 const minHeaderSize = 18
 const minPayloadSize = 46
@@ -60,17 +100,149 @@ const (
 	// The maximum frame size is 1518 bytes, 18 bytes of which are overhead (header and frame check sequence),
 	// resulting in an MTU of 1500 bytes.
 	MaxFrameSize = 1518
+	// DefaultMTU is the maximum payload size of the standard Ethernet frame.
+	DefaultMTU = 1500
+)
+
+// Offsets of the frame fields within a serialized frame. An 802.1Q tag
+// is inserted right after the source address, so every field starting
+// from the EtherType is shifted by 4 bytes (TPID + TCI) in a tagged frame.
+const (
+	OffsetDst             = 0
+	OffsetSrc             = 6
+	OffsetEtherType       = 12 // untagged frame
+	OffsetTPID            = 12 // tagged frame
+	OffsetTCI             = 14 // tagged frame
+	OffsetEtherTypeTagged = 16
+	OffsetPayload         = 14
+	OffsetPayloadTagged   = 18
 )
 
+// EtherTypeOffset returns the offset of the EtherType field, which depends
+// on whether the frame carries an 802.1Q tag.
+func EtherTypeOffset(tagged bool) int {
+	if tagged {
+		return OffsetEtherTypeTagged
+	}
+	return OffsetEtherType
+}
+
+// ErrNotMulticast is returned when a multicast frame is built with a unicast destination.
+var ErrNotMulticast = errors.New("destination address is not a multicast address")
+
+// ErrInvalidMinPayload is returned when the minimal payload size is negative.
+var ErrInvalidMinPayload = errors.New("minimal payload size must not be negative")
+
+// ErrPayloadTooLarge is returned when the payload doesn't fit into MaxFrameSize.
+var ErrPayloadTooLarge = errors.New("payload exceeds maximum frame size")
+
+// FrameOption configures a frame constructed by NewFrame or NewFrameE.
+type FrameOption func(o *frameOptions) error
+
+type frameOptions struct {
+	tag8021q   *Tag8021Q
+	minPayload int
+}
+
+// WithVLAN adds an 802.1Q tag with the given PCP, DEI and VLAN identifier.
+// Returns ErrInvalidPCP, ErrInvalidDEI or ErrInvalidVLAN if any of the values is out of range.
+func WithVLAN(pcp PCP, dei uint16, vlan uint16) FrameOption {
+	return func(o *frameOptions) error {
+		if err := Validate8021qTCI(pcp, dei, vlan); err != nil {
+			return err
+		}
+		o.tag8021q = &Tag8021Q{
+			TPID: uint16(EtherTypeVlan),
+			TCI:  Encode8021qTCI(pcp, dei, vlan),
+		}
+		return nil
+	}
+}
+
+// WithRawPayload disables padding of the payload to the minimal payload size,
+// it's the same as WithMinPayload(0).
+func WithRawPayload() FrameOption {
+	return WithMinPayload(0)
+}
+
+// WithMinPayload sets the padding floor of the payload, shorter payloads are padded
+// with zeroes to min bytes. The default of 46 bytes is required for the wire compatibility,
+// lower values are useful for internal virtual links and test harnesses.
+// Returns ErrInvalidMinPayload if min is negative.
+func WithMinPayload(min int) FrameOption {
+	return func(o *frameOptions) error {
+		if min < 0 {
+			return ErrInvalidMinPayload
+		}
+		o.minPayload = min
+		return nil
+	}
+}
+
 // NewFrame return constructed ethernet frame with basic source, destination MAC address
 // and payload which this frame contains. If payload have lengh which less than minPayloadSize
-// we fills remaining bytes with zeroes
-func NewFrame(src HardwareAddr, dst HardwareAddr, etherType EtherType, payload []byte) *Frame {
+// we fills remaining bytes with zeroes. The payload size isn't limited, so jumbo frames can be built.
+// NewFrame panics if any of the options fails, use NewFrameE to handle the error.
+func NewFrame(src HardwareAddr, dst HardwareAddr, etherType EtherType, payload []byte, opts ...FrameOption) *Frame {
+	f, err := newFrame(src, dst, etherType, payload, opts...)
+	if err != nil {
+		panic(err)
+	}
+	return f
+}
+
+// NewFrameFromTo is an alias of NewFrame with an unambiguous name: the frame is sent
+// from src to dst. Note that on the wire the destination address comes first.
+func NewFrameFromTo(src HardwareAddr, dst HardwareAddr, etherType EtherType, payload []byte, opts ...FrameOption) *Frame {
+	return NewFrame(src, dst, etherType, payload, opts...)
+}
+
+// NewFrameWithMinPayload is like NewFrame, but pads the payload to min bytes
+// instead of the standard 46 bytes, see WithMinPayload.
+func NewFrameWithMinPayload(min int, src HardwareAddr, dst HardwareAddr, etherType EtherType, payload []byte, opts ...FrameOption) *Frame {
+	// copy opts, appending in place would modify the backing array of the caller
+	return NewFrame(src, dst, etherType, payload, append(append([]FrameOption(nil), opts...), WithMinPayload(min))...)
+}
+
+// NewBroadcastFrame returns a frame sent from src to the BroadcastAddr.
+func NewBroadcastFrame(src HardwareAddr, etherType EtherType, payload []byte, opts ...FrameOption) *Frame {
+	return NewFrame(src, BroadcastAddr, etherType, payload, opts...)
+}
+
+// NewMulticastFrame is like NewFrameE, but returns ErrNotMulticast if
+// the multicast bit of dst is not set.
+func NewMulticastFrame(src HardwareAddr, dst HardwareAddr, etherType EtherType, payload []byte, opts ...FrameOption) (*Frame, error) {
+	if !dst.IsMulticast() {
+		return nil, ErrNotMulticast
+	}
+	return NewFrameE(src, dst, etherType, payload, opts...)
+}
+
+// NewFrameE is like NewFrame, but returns an error if any of the options fails,
+// or ErrPayloadTooLarge if the payload exceeds MaxFrameSize-minHeaderSize (1500) bytes.
+// Use NewFrame to build non-standard jumbo frames.
+func NewFrameE(src HardwareAddr, dst HardwareAddr, etherType EtherType, payload []byte, opts ...FrameOption) (*Frame, error) {
+	if len(payload) > MaxFrameSize-minHeaderSize {
+		return nil, ErrPayloadTooLarge
+	}
+	return newFrame(src, dst, etherType, payload, opts...)
+}
+
+func newFrame(src HardwareAddr, dst HardwareAddr, etherType EtherType, payload []byte, opts ...FrameOption) (*Frame, error) {
+	o := frameOptions{minPayload: minPayloadSize}
+	for _, opt := range opts {
+		if err := opt(&o); err != nil {
+			return nil, err
+		}
+	}
+
 	var b []byte
+	var pad int
 	pSz := len(payload)
-	if pSz < minPayloadSize {
-		b = make([]byte, minPayloadSize)
+	if pSz < o.minPayload {
+		b = make([]byte, o.minPayload)
 		copy(b[:pSz], payload)
+		pad = o.minPayload - pSz
 	} else {
 		b = payload
 	}
@@ -78,44 +250,155 @@ func NewFrame(src HardwareAddr, dst HardwareAddr, etherType EtherType, payload [
 	f := &Frame{
 		dst:       dst,
 		src:       src,
-		tag8021q:  nil,
+		tag8021q:  o.tag8021q,
 		etherType: etherType,
 		payload:   b,
+		padded:    pad + 1,
 	}
-	return f
+	return f, nil
 }
 
+// RawAddresses returns the addresses in the order they are laid out on the wire.
+//
+// NOTE: Ethernet puts the DESTINATION address first (bytes 0-5) and the SOURCE
+// address second (bytes 6-11), so first is Destination() and second is Source().
+// The order of NewFrame arguments (src, dst) is the opposite of the wire order.
+func (f *Frame) RawAddresses() (first, second [6]byte) { return f.dst, f.src }
+
 // Source return sender source address
 func (f *Frame) Source() HardwareAddr { return f.src }
 
 // Destination return destination address from source frame
 func (f *Frame) Destination() HardwareAddr { return f.dst }
 
+// SrcOUI returns Organisationally Unique Identifier of the source address
+func (f *Frame) SrcOUI() [3]byte { return f.src.Oui() }
+
+// DstOUI returns Organisationally Unique Identifier of the destination address
+func (f *Frame) DstOUI() [3]byte { return f.dst.Oui() }
+
 // EtherType is a two-octet field in an Ethernet frame.
 // It is used to indicate which protocol is encapsulated in the payload of the frame
 // and is used at the receiving end by the data link layer to determine how the payload is processed.
 // The same field is also used to indicate the size of some Ethernet frames.
 func (f *Frame) EtherType() EtherType { return f.etherType }
 
+// SetEtherType sets the EtherType, the change takes effect on the next Marshal.
+func (f *Frame) SetEtherType(etherType EtherType) {
+	f.etherType = etherType
+	f.invalidate()
+}
+
 // Payload the minimum payload is 42 octets when an 802.1Q tag (Tag8012q)
 // is present and 46 octets when absent. When the actual payload is less,
 // padding bytes are added accordingly. The maximum payload is 1500 octets.
 // Non-standard jumbo frames allow for larger maximum payload size.
 func (f *Frame) Payload() []byte { return f.payload }
 
+// PadLen returns the number of zero bytes NewFrame appended to the payload to reach
+// the minimum payload size, so the real data is Payload()[:len(Payload())-PadLen()].
+// Returns 0 if the payload wasn't padded, and -1 if it's unknown, e.g. for decoded
+// frames, where padding can't be told apart from the data.
+func (f *Frame) PadLen() int { return f.padded - 1 }
+
+// PayloadReader returns a reader over the payload without copying it.
+// The padding bytes appended by NewFrame (see PadLen) are excluded, the unknown
+// padding of decoded frames is included.
+func (f *Frame) PayloadReader() io.Reader {
+	if n := f.PadLen(); n > 0 {
+		return bytes.NewReader(f.payload[:len(f.payload)-n])
+	}
+	return bytes.NewReader(f.payload)
+}
+
+// SetPayload sets the payload as is, without padding (PadLen is 0), the frame aliases payload.
+func (f *Frame) SetPayload(payload []byte) {
+	f.payload = payload
+	f.padded = 1
+	f.invalidate()
+}
+
 // Tag8021Q IEEE 802.1Q, often referred to as Dot1q, is the networking standard that
 // supports virtual LANs (VLANs) on an IEEE 802.3 Ethernet network.
 // The standard defines a system of VLAN tagging for Ethernet frames and the accompanying
 // procedures to be used by bridges and switches in handling such frames.
 // The standard also contains provisions for a quality-of-service (QOS) prioritization scheme commonly
 // known as IEEE 802.1p and defines the Generic Attribute Registration Protocol.
-func (f *Frame) Tag8021Q() *Tag8021Q       { return f.tag8021q }
-func (f *Frame) SetTag8021Q(tag *Tag8021Q) { f.tag8021q = tag }
+func (f *Frame) Tag8021Q() *Tag8021Q { return f.tag8021q }
+func (f *Frame) SetTag8021Q(tag *Tag8021Q) {
+	f.tag8021q = tag
+	f.invalidate()
+}
+
+// OuterTPID returns the TPID of the outer tag as seen on the wire, e.g. 0x88A8 for
+// S-tagged (802.1ad) frames, or false if the frame is untagged.
+func (f *Frame) OuterTPID() (uint16, bool) {
+	if f.tag8021q == nil {
+		return 0, false
+	}
+	return f.tag8021q.TPID, true
+}
+
+// InnerTags returns the stacked (QinQ) tags following the outer tag returned by Tag8021Q,
+// ordered from the outermost to the innermost.
+func (f *Frame) InnerTags() []Tag8021Q { return f.inner }
+
+// SetInnerTags sets the stacked (QinQ) tags, which are encoded after the outer tag.
+func (f *Frame) SetInnerTags(tags []Tag8021Q) {
+	f.inner = tags
+	f.invalidate()
+}
 
 // Frame Check Sequence (FCS) refers to the extra bits and characters added to
 // data packets for error detection and control.
-func (f *Frame) FCS() [4]byte       { return f.fcs }
-func (f *Frame) SetFCS(fcs [4]byte) { f.fcs = fcs }
+func (f *Frame) FCS() [4]byte { return f.fcs }
+func (f *Frame) SetFCS(fcs [4]byte) {
+	f.fcs = fcs
+	f.invalidate()
+}
+
+// IsTruncated reports whether the frame was truncated by the capture (snaplen),
+// so its payload is incomplete and its FCS is missing.
+func (f *Frame) IsTruncated() bool { return f.origLen > f.capLen }
+
+// CaptureLength returns the captured and the original length of the truncated frame,
+// for other frames both are Size().
+func (f *Frame) CaptureLength() (captured, original int) {
+	if !f.IsTruncated() {
+		return f.Size(), f.Size()
+	}
+	return f.capLen, f.origLen
+}
+
+// unmarshalTruncated decodes the frame truncated from origLen to len(b) bytes,
+// the truncated frame has no FCS.
+func unmarshalTruncated(b []byte, origLen int, f *Frame) error {
+	if err := (UnmarshalOptions{NoFCS: true}).Unmarshal(b, f); err != nil {
+		return err
+	}
+	f.capLen, f.origLen = len(b), origLen
+	return nil
+}
+
+// FCSMode returns the FCS mode used by marshaling, see SetFCSMode.
+func (f *Frame) FCSMode() FCSMode { return f.fcsMode }
+
+// SetFCSMode sets the FCS mode, in FCSKeep mode the FCS set by SetFCS is marshaled verbatim
+// instead of being recomputed.
+func (f *Frame) SetFCSMode(mode FCSMode) {
+	f.fcsMode = mode
+	f.invalidate()
+}
+
+// FCSUint32 returns the FCS decoded as big-endian uint32,
+// comparable with the crc32.ChecksumIEEE output.
+func (f *Frame) FCSUint32() uint32 { return binary.BigEndian.Uint32(f.fcs[:]) }
+
+// SetFCSUint32 sets the FCS encoded as big-endian.
+func (f *Frame) SetFCSUint32(v uint32) {
+	binary.BigEndian.PutUint32(f.fcs[:], v)
+	f.invalidate()
+}
 
 // Size return a serialized size of frame in bytes
 func (f *Frame) Size() int {
@@ -123,11 +406,129 @@ func (f *Frame) Size() int {
 	if f.tag8021q != nil {
 		tsz += 4
 	}
+	tsz += 4 * len(f.inner)
 	// minHeaderSize is
 	// 6 bytes DST + 6 bytes SRC + 4 bytes FCS
 	return minHeaderSize + tsz + len(f.payload)
 }
 
+// CopyTo deep-copies the frame into dst. The dst payload backing array is reused
+// when its capacity is large enough, and grown only when needed, so a recycled dst
+// doesn't allocate for the payload.
+func (f *Frame) CopyTo(dst *Frame) {
+	dst.dst = f.dst
+	dst.src = f.src
+	if f.tag8021q != nil {
+		tag := *f.tag8021q
+		dst.tag8021q = &tag
+	} else {
+		dst.tag8021q = nil
+	}
+	dst.inner = append(dst.inner[:0], f.inner...)
+	if len(f.inner) == 0 {
+		dst.inner = nil
+	}
+	dst.etherType = f.etherType
+	if cap(dst.payload) < len(f.payload) {
+		dst.payload = make([]byte, len(f.payload))
+	}
+	dst.payload = dst.payload[:len(f.payload)]
+	copy(dst.payload, f.payload)
+	dst.fcs = f.fcs
+	dst.fcsMode = f.fcsMode
+	dst.capLen, dst.origLen = f.capLen, f.origLen
+	dst.padded = f.padded
+	dst.invalidate()
+	dst.trailer = append(dst.trailer[:0], f.trailer...)
+	if len(f.trailer) == 0 {
+		dst.trailer = nil
+	}
+}
+
+// CloneWithPayload returns a new frame with the addresses, EtherType and tags of f,
+// carrying a copy of p padded to the minimum payload size like NewFrame. The clone shares
+// nothing with f or p, so each consumer of the replicated frame can modify its own copy.
+// The FCS of f doesn't match the new payload, so the clone is always in FCSCompute mode.
+func (f *Frame) CloneWithPayload(p []byte) *Frame {
+	size := len(p)
+	if size < minPayloadSize {
+		size = minPayloadSize
+	}
+	payload := make([]byte, size)
+	copy(payload, p)
+
+	clone := &Frame{
+		dst:       f.dst,
+		src:       f.src,
+		etherType: f.etherType,
+		payload:   payload,
+		padded:    size - len(p) + 1,
+	}
+	if f.tag8021q != nil {
+		tag := *f.tag8021q
+		clone.tag8021q = &tag
+	}
+	if len(f.inner) > 0 {
+		clone.inner = append([]Tag8021Q(nil), f.inner...)
+	}
+	return clone
+}
+
+// Reset zeroes all fields of the frame, releasing the payload and the 802.1Q tag references,
+// so a pooled frame can be reused without retaining the previous payload.
+func (f *Frame) Reset() {
+	*f = Frame{}
+}
+
+var framesPool = &sync.Pool{
+	New: func() interface{} {
+		return new(Frame)
+	},
+}
+
+// AcquireFrame returns an empty frame from the pool,
+// the frame should be returned to the pool with ReleaseFrame.
+func AcquireFrame() *Frame {
+	return framesPool.Get().(*Frame)
+}
+
+// ReleaseFrame resets the frame and returns it to the pool.
+// The frame must not be used after the release.
+func ReleaseFrame(f *Frame) {
+	f.Reset()
+	framesPool.Put(f)
+}
+
+// FitsMTU reports whether the frame fits into a link with the given MTU,
+// if mtu <= 0 then DefaultMTU is used. The 802.1Q tag is counted against the MTU,
+// so a tagged frame fits only if payload + 4 bytes per tag <= mtu.
+// Ethernet itself doesn't fragment frames, so this is a pre-send validity check,
+// the frame which doesn't fit must be fragmented by the upper layer protocol.
+func (f *Frame) FitsMTU(mtu int) bool {
+	if mtu <= 0 {
+		mtu = DefaultMTU
+	}
+	n := len(f.payload) + 4*len(f.inner)
+	if f.tag8021q != nil {
+		n += 4
+	}
+	return n <= mtu
+}
+
+// ExpectedFrameSize returns the serialized size of a frame built by NewFrame
+// for the payload of payloadLen bytes, the payload is padded to the minimal
+// payload size, and 4 bytes are added for the 802.1Q tag.
+func ExpectedFrameSize(payloadLen int, tagged bool) int {
+	if payloadLen < minPayloadSize {
+		payloadLen = minPayloadSize
+	}
+	n := minHeaderSize + payloadLen
+	if tagged {
+		n += 4
+	}
+	return n
+}
+
 var framePool = &sync.Pool{
 	New: func() interface{} {
 		return make([]byte, MaxFrameSize)
@@ -138,8 +539,116 @@ func (f *Frame) marshal() []byte {
 	b := framePool.Get().([]byte)
 	defer framePool.Put(b)
 
-	b = b[:0]
-	b = append(b, f.dst[:]...)
+	return f.MarshalAppend(b[:0])
+}
+
+// Marshal serializes frame into the byte representation.
+// If the structure contains 802.1Q tag, performs an additional
+// encoding of the 802.1Q header within the frame.
+// The computed FCS is stored into the frame, see MarshalReadOnly.
+// With SetMarshalCache the output of the unchanged frame is reused.
+func (f *Frame) Marshal() []byte {
+	if !f.cacheOn {
+		return f.marshal()
+	}
+	if f.dirty || f.cache == nil {
+		f.cache = f.MarshalAppend(f.cache[:0])
+		f.dirty = false
+	}
+	return f.cache
+}
+
+// SetMarshalCache enables caching of Marshal output, for frames passed through multiple
+// stages which marshal them unchanged. The cache is invalidated by the setters and the other
+// modifying methods of the frame, then the next Marshal encodes the frame again into the
+// same buffer, so the returned bytes are valid until the frame is modified. Modifications
+// through the returned references (Payload, Tag8021Q) are not tracked, set the modified
+// value back with SetPayload or SetTag8021Q to invalidate the cache.
+func (f *Frame) SetMarshalCache(enabled bool) {
+	f.cacheOn = enabled
+	f.cache = nil
+	f.dirty = false
+}
+
+// invalidate marks the cached Marshal output as stale.
+func (f *Frame) invalidate() {
+	if f.cacheOn {
+		f.dirty = true
+	}
+}
+
+// Rebuild is FixFCS, the explicit way to re-encode a frame after Unmarshal: the payload
+// keeps aliasing the decoded buffer, but the result never does, so the decoded buffer
+// is left intact.
+func (f *Frame) Rebuild() []byte { return f.FixFCS() }
+
+// MarshalReadOnly is like Marshal, but doesn't modify the frame: the FCS is computed
+// only into the returned buffer and FCS() keeps the previous value. The returned buffer
+// is newly allocated, so the same frame can be marshaled by multiple goroutines.
+func (f *Frame) MarshalReadOnly() []byte {
+	b := f.appendHeaderPayload(make([]byte, 0, f.Size()))
+	if f.fcsMode == FCSKeep {
+		return append(b, f.fcs[:]...)
+	}
+	sum := crc32.ChecksumIEEE(b)
+	return append(b, byte(sum>>24), byte(sum>>16), byte(sum>>8), byte(sum))
+}
+
+// MarshalAppend appends serialized frame (with FCS) to the dst and returns
+// the extended buffer. The FCS is calculated only over the appended region,
+// so dst can already contain other frames.
+func (f *Frame) MarshalAppend(dst []byte) []byte {
+	start := len(dst)
+	b := f.appendHeaderPayload(dst)
+	if f.fcsMode == FCSKeep {
+		return append(b, f.fcs[:]...)
+	}
+	sum := crc32.ChecksumIEEE(b[start:])
+	f.fcs = [4]byte{
+		byte(sum >> 24),
+		byte(sum >> 16),
+		byte(sum >> 8), byte(sum),
+	}
+	b = append(b, f.fcs[:]...)
+	return b
+}
+
+// MarshalTo serializes the frame into dst and returns the number of bytes written.
+// Returns io.ErrShortBuffer if dst is shorter than Size().
+func (f *Frame) MarshalTo(dst []byte) (int, error) {
+	if len(dst) < f.Size() {
+		return 0, io.ErrShortBuffer
+	}
+	return len(f.MarshalAppend(dst[:0])), nil
+}
+
+// MarshalWith is like MarshalTo, but calculates the FCS with the caller provided hash h,
+// which is Reset before use. The hash is expected to be crc32.NewIEEE(), reusing it
+// across calls avoids allocation of the hash state.
+func (f *Frame) MarshalWith(dst []byte, h hash.Hash32) (int, error) {
+	if len(dst) < f.Size() {
+		return 0, io.ErrShortBuffer
+	}
+	b := f.appendHeaderPayload(dst[:0])
+	if f.fcsMode == FCSKeep {
+		return len(append(b, f.fcs[:]...)), nil
+	}
+	h.Reset()
+	h.Write(b)
+	sum := h.Sum32()
+	f.fcs = [4]byte{
+		byte(sum >> 24),
+		byte(sum >> 16),
+		byte(sum >> 8),
+		byte(sum),
+	}
+	b = append(b, f.fcs[:]...)
+	return len(b), nil
+}
+
+// appendHeaderPayload appends serialized frame without FCS to the dst.
+func (f *Frame) appendHeaderPayload(dst []byte) []byte {
+	b := append(dst, f.dst[:]...)
 	b = append(b, f.src[:]...)
 	if f.tag8021q != nil {
 		b = append(b,
@@ -151,57 +660,200 @@ func (f *Frame) marshal() []byte {
 			byte(f.tag8021q.TCI),
 		)
 	}
+	for _, tag := range f.inner {
+		b = append(b,
+			byte(tag.TPID>>8),
+			byte(tag.TPID),
+			byte(tag.TCI>>8),
+			byte(tag.TCI),
+		)
+	}
 	b = append(b,
 		byte(f.etherType>>8),
 		byte(f.etherType),
 	)
-	b = append(b, f.payload...)
+	return append(b, f.payload...)
+}
 
-	sum := crc32.ChecksumIEEE(b[:])
-	f.fcs = [4]byte{
-		byte(sum >> 24),
-		byte(sum >> 16),
-		byte(sum >> 8), byte(sum),
+// checksum calculates the FCS of the frame without modifying it.
+func (f *Frame) checksum() uint32 {
+	b := framePool.Get().([]byte)
+	defer framePool.Put(b)
+
+	// ChecksumIEEE already picks the fastest implementation available: the
+	// carry-less multiplication on amd64/arm64/s390x, and slicing-by-8 otherwise.
+	return crc32.ChecksumIEEE(f.appendHeaderPayload(b[:0]))
+}
+
+// PayloadCRC32 returns IEEE CRC-32 of the payload (including the padding), so the same
+// upper layer PDU can be matched across frames with different headers.
+func (f *Frame) PayloadCRC32() uint32 { return crc32.ChecksumIEEE(f.payload) }
+
+// PayloadCRC32Table is like PayloadCRC32, but uses the polynomial of the table tab,
+// e.g. crc32.MakeTable(crc32.Castagnoli).
+func (f *Frame) PayloadCRC32Table(tab *crc32.Table) uint32 { return crc32.Checksum(f.payload, tab) }
+
+// FingerprintSHA256 returns SHA-256 of the serialized frame excluding the FCS,
+// so frames which differ only in FCS share the fingerprint.
+// The VLAN tags are included, use FingerprintSHA256Untagged to exclude them.
+func (f *Frame) FingerprintSHA256() [32]byte {
+	b := framePool.Get().([]byte)
+	defer framePool.Put(b)
+
+	return sha256.Sum256(f.appendHeaderPayload(b[:0]))
+}
+
+// FingerprintSHA256Untagged is like FingerprintSHA256, but excludes the VLAN tags,
+// so the same frame seen on different VLANs shares the fingerprint.
+func (f *Frame) FingerprintSHA256Untagged() [32]byte {
+	untagged := *f
+	untagged.tag8021q = nil
+	untagged.inner = nil
+	return untagged.FingerprintSHA256()
+}
+
+// VerifyFCS reports whether the stored FCS matches the frame fields,
+// e.g. after Unmarshal or after the fields were modified.
+func (f *Frame) VerifyFCS() bool {
+	return f.checksum() == f.FCSUint32()
+}
+
+// FixFCS recomputes the FCS after the frame was modified, regardless of FCSMode,
+// stores it and returns the frame marshaled into a newly allocated buffer owned by the caller.
+func (f *Frame) FixFCS() []byte {
+	b := f.appendHeaderPayload(make([]byte, 0, f.Size()))
+	f.SetFCSUint32(crc32.ChecksumIEEE(b))
+	return append(b, f.fcs[:]...)
+}
+
+// VerifyFCS reports whether the last 4 bytes of the serialized frame b
+// match the checksum of the preceding bytes.
+func VerifyFCS(b []byte) bool {
+	sz := len(b)
+	if sz < 4 {
+		return false
 	}
-	b = append(b, f.fcs[:]...)
-	return b
+	return crc32.ChecksumIEEE(b[:sz-4]) == binary.BigEndian.Uint32(b[sz-4:])
 }
 
-// Marshal serializes frame into the byte representation.
-// If the structure contains 802.1Q tag, performs an additional
-// encoding of the 802.1Q header within the frame.
-func (f *Frame) Marshal() []byte {
-	return f.marshal()
+// Trailer returns the bytes following the FCS, which some NICs append to the frame.
+// It's set only by UnmarshalOptions with TrailerLen, and is not encoded by Marshal.
+func (f *Frame) Trailer() []byte { return f.trailer }
+
+// UnmarshalOptions configures decoding of the frame.
+type UnmarshalOptions struct {
+	// TrailerLen is the number of bytes appended after the FCS (e.g. by a NIC
+	// for VLAN re-insertion or debugging), which are split off into Trailer.
+	// The default of 0 means there is no trailer.
+	TrailerLen int
+	// NoFCS is set when the frame was captured without FCS (e.g. stripped by the driver),
+	// so the entire buffer after the header is the payload. The FCS of the decoded frame
+	// is left zero, and the buffer must hold only the header (14 bytes or more).
+	NoFCS bool
+	// MaxTags limits the number of stacked VLAN tags, the default of 0 means DefaultMaxTags.
+	MaxTags int
 }
 
+// DefaultMaxTags is the default limit of stacked VLAN tags (outer and inner tag of QinQ).
+const DefaultMaxTags = 2
+
+// ErrTooManyTags is returned when the frame has more stacked VLAN tags than allowed.
+var ErrTooManyTags = errors.New("too many stacked VLAN tags")
+
 // Unmarshal unmarshaling a sequence of bytes into a Frame structure representation.
+// Any of VLANTPIDs is decoded as an 802.1Q tag, storing the actual TPID. Stacked (QinQ)
+// tags following the outer tag are decoded into InnerTags, a frame with more than
+// DefaultMaxTags tags in total is rejected with ErrTooManyTags.
 // If array size is less than minSize (64) returns error io.ErrUnexpectedEOF
 func Unmarshal(b []byte, f *Frame) error {
-	sz := len(b)
-	if sz < MinFrameSizeWithoutFCS {
+	return UnmarshalOptions{}.Unmarshal(b, f)
+}
+
+// UnmarshalCopy is like Unmarshal, but decodes a copy of b, so the frame doesn't alias b
+// and the buffer can be reused by the caller.
+func UnmarshalCopy(b []byte, f *Frame) error {
+	return Unmarshal(append([]byte(nil), b...), f)
+}
+
+// UnmarshalMany decodes each of chunks with UnmarshalCopy, so the frames don't alias
+// the chunks, and a bad chunk doesn't abort the batch. The returned slices are parallel
+// to chunks: frames[i] is the decoded chunk i, or nil if errs[i] is not nil.
+func UnmarshalMany(chunks [][]byte) ([]*Frame, []error) {
+	frames := make([]*Frame, len(chunks))
+	errs := make([]error, len(chunks))
+	for i, b := range chunks {
+		f := new(Frame)
+		if err := UnmarshalCopy(b, f); err != nil {
+			errs[i] = err
+			continue
+		}
+		frames[i] = f
+	}
+	return frames, errs
+}
+
+// Unmarshal is like the package level Unmarshal, but decodes according to the options.
+func (o UnmarshalOptions) Unmarshal(b []byte, f *Frame) error {
+	minSize, fcsLen := MinFrameSizeWithoutFCS, 4
+	if o.NoFCS {
+		minSize, fcsLen = OffsetPayload, 0
+	}
+	if o.TrailerLen < 0 || len(b) < minSize+o.TrailerLen {
 		return io.ErrUnexpectedEOF
 	}
+	// decode into locals, so f is left untouched on error
+	var trailer []byte
+	if o.TrailerLen > 0 {
+		trailer = b[len(b)-o.TrailerLen:]
+		b = b[:len(b)-o.TrailerLen]
+	}
+	sz := len(b)
 
-	var n int
-	copy(f.dst[:], b[:6])
-	n += 6
-	copy(f.src[:], b[n:n+6])
-	n += 6
+	var (
+		tag8021q *Tag8021Q
+		inner    []Tag8021Q
+	)
+	maxTags := o.MaxTags
+	if maxTags <= 0 {
+		maxTags = DefaultMaxTags
+	}
+	n := OffsetEtherType
 	etype := EtherType(binary.BigEndian.Uint16(b[n : n+2]))
-	if etype == EtherTypeVlan {
-		// have a 802.1Q tag
-		f.tag8021q = new(Tag8021Q)
-		f.tag8021q.TPID = uint16(etype)
-		f.tag8021q.TCI = binary.BigEndian.Uint16(b[n+2 : n+4])
-		f.etherType = EtherType(binary.BigEndian.Uint16(b[n+4 : n+6]))
-		n += 6
-	} else {
-		f.etherType = etype
-		n += 2
+	for tags := 0; isVLANTPID(uint16(etype)); tags++ {
+		if tags == maxTags {
+			return ErrTooManyTags
+		}
+		// have a 802.1Q tag, check that TCI and the next EtherType fit before FCS
+		if n+6 > sz-fcsLen {
+			return io.ErrUnexpectedEOF
+		}
+		tag := Tag8021Q{
+			TPID: uint16(etype),
+			TCI:  binary.BigEndian.Uint16(b[n+2 : n+4]),
+		}
+		if tag8021q == nil {
+			tag8021q = &tag
+		} else {
+			// stacked (QinQ) tag
+			inner = append(inner, tag)
+		}
+		n += 4
+		etype = EtherType(binary.BigEndian.Uint16(b[n : n+2]))
 	}
+	n += 2
 
-	f.payload = b[n : sz-4]
+	copy(f.dst[:], b[OffsetDst:OffsetDst+6])
+	copy(f.src[:], b[OffsetSrc:OffsetSrc+6])
+	f.tag8021q = tag8021q
+	f.inner = inner
+	f.etherType = etype
+	f.trailer = trailer
+	f.capLen, f.origLen = 0, 0
+	f.padded = 0
+	f.invalidate()
+	f.payload = b[n : sz-fcsLen]
 	n += len(f.payload)
+	f.fcs = [4]byte{}
 	copy(f.fcs[:], b[n:])
 	return nil
 }
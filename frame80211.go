@@ -5,9 +5,9 @@ package ethernet
 
 import (
 	"encoding/binary"
+	"errors"
 	"hash/crc32"
 	"io"
-	"sync"
 )
 
 // IEEE 802.11 is part of the IEEE 802 set of local area network (LAN) technical standards,
@@ -35,14 +35,42 @@ type Frame80211 struct {
 	fcs     [4]byte
 }
 
-var min80211Size = 30
+// min80211Size is the size of the three-address header with sequence control and FCS.
+var min80211Size = 28
 
+// ErrNotDataFrame is returned when the operation requires an 802.11 data frame.
+var ErrNotDataFrame = errors.New("not an 802.11 data frame")
+
+// ErrAddr4NotWDS is returned when the fourth address is provided for a frame,
+// which isn't a WDS frame (ToDS and FromDS are not both set).
+var ErrAddr4NotWDS = errors.New("address 4 is present only in WDS frames (ToDS and FromDS)")
+
+// ErrBodyTooLarge is returned when the frame body exceeds Max80211Body.
+var ErrBodyTooLarge = errors.New("802.11 frame body is too large")
+
+// NewFrame80211E returns a new 802.11 frame. The addr4 is present only in WDS frames,
+// so it returns ErrAddr4NotWDS if addr4 is not nil, but frame control doesn't have
+// both ToDS and FromDS bits set. Returns ErrBodyTooLarge if the payload is longer
+// than Max80211Body.
+func NewFrame80211E(addr1, addr2, addr3 HardwareAddr, addr4 *HardwareAddr, fc uint16, duration uint16, payload []byte) (*Frame80211, error) {
+	if len(payload) > Max80211Body {
+		return nil, ErrBodyTooLarge
+	}
+	f := NewFrame80211(addr1, addr2, addr3, addr4, fc, duration, payload)
+	if addr4 != nil && !f.IsWDS() {
+		return nil, ErrAddr4NotWDS
+	}
+	return f, nil
+}
+
+// NewFrame80211 returns a new 802.11 frame. Neither the body size nor addr4 is
+// checked, addr4 of a non-WDS frame is kept, but not encoded.
 func NewFrame80211(addr1, addr2, addr3 HardwareAddr, addr4 *HardwareAddr, fc uint16, duration uint16, payload []byte) *Frame80211 {
 	f := &Frame80211{
 		fc:       fc,
 		duration: duration,
 		addr1:    addr1,
-		addr2:    addr1,
+		addr2:    addr2,
 		addr3:    addr3,
 		payload:  payload,
 	}
@@ -52,6 +80,47 @@ func NewFrame80211(addr1, addr2, addr3 HardwareAddr, addr4 *HardwareAddr, fc uin
 	return f
 }
 
+// NewDataFrame returns a data frame with the frame control computed from the
+// DS bits, and the addresses assigned according to the DS combination:
+//
+//	ToDS FromDS  Addr1  Addr2  Addr3  Addr4
+//	0    0       DA     SA     BSSID  -
+//	0    1       DA     BSSID  SA     -
+//	1    0       BSSID  SA     DA     -
+//	1    1       RA     TA     DA     SA
+//
+// In the WDS case (ToDS and FromDS) both RA and TA are set to bssid.
+func NewDataFrame(toDS, fromDS bool, bssid, src, dst HardwareAddr, payload []byte) *Frame80211 {
+	var tds, fds uint16
+	if toDS {
+		tds = 1
+	}
+	if fromDS {
+		fds = 1
+	}
+	fc := Encode80211Fc(0, uint16(Data), SubtypeData, tds, fds, 0, 0, 0, 0, 0, 0)
+
+	switch {
+	case !toDS && !fromDS:
+		return NewFrame80211(dst, src, bssid, nil, fc, 0, payload)
+	case !toDS && fromDS:
+		return NewFrame80211(dst, bssid, src, nil, fc, 0, payload)
+	case toDS && !fromDS:
+		return NewFrame80211(bssid, src, dst, nil, fc, 0, payload)
+	default:
+		return NewFrame80211(bssid, bssid, dst, &src, fc, 0, payload)
+	}
+}
+
+// NewDataFrameE is like NewDataFrame, but returns ErrBodyTooLarge if the payload
+// is longer than Max80211Body.
+func NewDataFrameE(toDS, fromDS bool, bssid, src, dst HardwareAddr, payload []byte) (*Frame80211, error) {
+	if len(payload) > Max80211Body {
+		return nil, ErrBodyTooLarge
+	}
+	return NewDataFrame(toDS, fromDS, bssid, src, dst, payload), nil
+}
+
 // Receiver return Receiver Address (RA)
 func (f *Frame80211) Receiver() HardwareAddr { return f.addr1 }
 
@@ -88,6 +157,35 @@ func (f *Frame80211) Destination() HardwareAddr {
 	return da
 }
 
+// IsWDS reports whether both ToDS and FromDS bits are set,
+// in which case the frame carries the fourth address.
+func (f *Frame80211) IsWDS() bool {
+	return (f.fc>>8)&1 == 1 && (f.fc>>9)&1 == 1
+}
+
+// Type returns the frame type from the frame control.
+func (f *Frame80211) Type() FrameType { return FrameType((f.fc >> 2) & 3) }
+
+// Subtype returns the frame subtype from the frame control.
+func (f *Frame80211) Subtype() uint16 { return (f.fc >> 4) & 15 }
+
+// Retry reports whether the Retry bit is set, i.e. the frame is a retransmission.
+func (f *Frame80211) Retry() bool { return (f.fc>>11)&1 == 1 }
+
+// Protected reports whether the Protected Frame (WEP) bit is set.
+func (f *Frame80211) Protected() bool { return (f.fc>>14)&1 == 1 }
+
+// CipherOverhead returns the number of bytes preceding and following the encrypted
+// body within the payload, so the encrypted data is payload[prefix:len(payload)-suffix].
+// The cipher isn't signalled in the frame, so it must be provided by the caller.
+// Returns zeroes if the frame is not protected.
+func (f *Frame80211) CipherOverhead(cipher Cipher) (prefix, suffix int) {
+	if !f.Protected() {
+		return 0, 0
+	}
+	return cipher.Overhead()
+}
+
 // Payload return payload data, maximum payload size defined in max80211MSDU
 func (f *Frame80211) Payload() []byte { return f.payload }
 
@@ -96,6 +194,20 @@ func (f *Frame80211) Payload() []byte { return f.payload }
 func (f *Frame80211) Duration() uint16            { return f.duration }
 func (f *Frame80211) SetDuration(duration uint16) { f.duration = duration }
 
+// DurationID interprets the Duration/ID field. In PS-Poll frames the field carries
+// the Association ID (AID) in the lower 14 bits with the top two bits set, in other
+// frames it carries the NAV duration in microseconds (bit 15 is 0). Values with
+// bit 15 set in other frames are reserved or CFP markers, and returned as nav 0.
+func (f *Frame80211) DurationID() (nav uint16, aid uint16, isAID bool) {
+	if f.Type() == Control && f.Subtype() == SubtypePsPoll && f.duration>>14 == 3 {
+		return 0, f.duration & aidMask, true
+	}
+	if f.duration>>15 == 0 {
+		return f.duration, 0, false
+	}
+	return 0, 0, false
+}
+
 // 802.11 Control Frames assist with the delivery of Data & Management frames.
 // Unlike management & data frames, Control frames does not have a frame body
 func (f *Frame80211) FrameControl() uint16      { return f.fc }
@@ -104,9 +216,78 @@ func (f *Frame80211) SetFrameControl(fc uint16) { f.fc = fc }
 func (f *Frame80211) SC() uint16      { return f.sc }
 func (f *Frame80211) SetSC(sc uint16) { f.sc = sc }
 
+// HasBody reports whether the frame carries a frame body, so Payload is meaningful.
+// PS-Poll, RTS, CTS, ACK, CF-End and CF-End+CF-Ack control frames have no body, the body
+// of other control frames follows the addresses (e.g. BlockAckReq and BlockAck control
+// and information). The data frames of "no data" subtypes (Null, QoS Null, CF-Ack,
+// CF-Poll and CF-Ack+CF-Poll), which have the bit 0x4 set, have no body either.
+func (f *Frame80211) HasBody() bool {
+	switch f.Type() {
+	case Control:
+		switch f.Subtype() {
+		case SubtypePsPoll, SubtypeRts, SubtypeCts, SubtypeAck, SubtypeCfEnd, SubtypeCfEndAck:
+			return false
+		default:
+			return true
+		}
+	case Data:
+		return f.Subtype()&subtypeNoData == 0
+	default:
+		return true
+	}
+}
+
+// subtypeNoData is the bit of the data frame subtype signalling absence of the frame body.
+const subtypeNoData = 0x4
+
+// controlAddrs returns the number of addresses in the control frame,
+// CTS, ACK and Control Wrapper carry only RA, other control frames carry RA and TA.
+func (f *Frame80211) controlAddrs() int {
+	switch f.Subtype() {
+	case SubtypeCts, SubtypeAck, SubtypeControlWrapper:
+		return 1
+	default:
+		return 2
+	}
+}
+
+// aidMask is the bits of Duration/ID field carrying the AID.
+const aidMask = 0x3FFF
+
+// NewPSPoll returns a new PS-Poll control frame sent by the station ta with the
+// association ID aid to the AP bssid. The AID is carried in the Duration/ID field with
+// the top two bits set, bits of aid above 14 are ignored. The frame has no body.
+// On decode the fields are available as Receiver (BSSID), Transmitter (TA) and DurationID.
+func NewPSPoll(aid uint16, bssid, ta HardwareAddr) *Frame80211 {
+	fc := Encode80211Fc(0, uint16(Control), SubtypePsPoll, 0, 0, 0, 0, 0, 0, 0, 0)
+	return NewFrame80211(bssid, ta, HardwareAddr{}, nil, fc, 0xC000|aid&aidMask, nil)
+}
+
+// NewCTSToSelf returns a new CTS control frame, which the station ra sends to itself to
+// reserve the medium for duration microseconds (legacy protection). CTS carries only
+// the receiver address, so the frame is FC + Duration + RA + FCS (14 bytes).
+func NewCTSToSelf(duration uint16, ra HardwareAddr) *Frame80211 {
+	fc := Encode80211Fc(0, uint16(Control), SubtypeCts, 0, 0, 0, 0, 0, 0, 0, 0)
+	return NewFrame80211(ra, HardwareAddr{}, HardwareAddr{}, nil, fc, duration, nil)
+}
+
+// HasQoS reports whether the QoS Control field is present, which is
+// determined by the QoS bit (0x08) of the data frame subtype.
+func (f *Frame80211) HasQoS() bool {
+	return f.Type() == Data && f.Subtype()&SubtypeQosData != 0
+}
+
+// QOS returns the QoS Control field, which is encoded only if HasQoS is true.
 func (f *Frame80211) QOS() uint16       { return f.qos }
 func (f *Frame80211) SetQOS(qos uint16) { f.qos = qos }
 
+// HasHTC reports whether the HT Control field is present, which is determined
+// by the Order bit of QoS data and management frames.
+func (f *Frame80211) HasHTC() bool {
+	return (f.fc>>15)&1 == 1 && (f.HasQoS() || f.Type() == Management)
+}
+
+// HT returns the HT Control field, which is encoded only if HasHTC is true.
 func (f *Frame80211) HT() uint32      { return f.htc }
 func (f *Frame80211) SetHT(ht uint32) { f.htc = ht }
 
@@ -117,50 +298,80 @@ func (f *Frame80211) SetFCS(fcs [4]byte) { f.fcs = fcs }
 
 // Size return seriailized size of frame in bytes
 func (f *Frame80211) Size() int {
+	if f.Type() == Control {
+		// frame control + duration + RA (+ TA) (+ body) + FCS
+		n := 2 + 2 + 6*f.controlAddrs() + 4
+		if f.HasBody() {
+			n += len(f.payload)
+		}
+		return n
+	}
 	// MANDATORY!
 	// n:2 = frame control
 	// n+2 = duration
 	// n+6 = receiver address
 	// n+6 = transmitter address
 	// n+6 = source address
-	n := 2 + 2 + 6 + 6 + 6
 	// n+2 = sequence control
-	if f.sc != 0 {
-		n += 2
-	}
-	// 	// n+(0 or 6) = destination address
-	if !f.addr4.IsEmpty() {
+	n := 2 + 2 + 6 + 6 + 6 + 2
+	// n+(0 or 6) = source address in WDS frames
+	if f.IsWDS() {
 		n += 6
 	}
 	// n+(0 or 2) = QOS Control
-	if f.qos != 0 {
+	if f.HasQoS() {
 		n += 2
 	}
 	// n+(0 or 4) = HT Control
-	if f.htc != 0 {
+	if f.HasHTC() {
 		n += 4
 	}
 	// n+len(payload) = payload
-	n += len(f.payload)
+	if f.HasBody() {
+		n += len(f.payload)
+	}
 	// n+4 = FCS
 	n += 4 // fcs
 	return n
 }
 
 // 802.11 frames are capable of transporting frames with an MSDU payload of 2,304 bytes of upper layer data.
-const MaxFrame8011Size = 2304
+const MaxFrame8011Size = MaxMSDU
 
-var frame80211Pool = &sync.Pool{
-	New: func() interface{} {
-		return make([]byte, MaxFrame8011Size)
-	},
-}
+// Maximum sizes of the 802.11 frame body:
+//   - MaxMSDU applies to a frame which carries a single MSDU (no aggregation).
+//   - MaxAMSDU7935 applies to HT (802.11n) frames carrying A-MSDU, when
+//     the station advertises the maximum A-MSDU length of 7935 bytes.
+//   - MaxAMSDU11454 applies to VHT (802.11ac) and later frames, it's the
+//     maximum MPDU length which bounds an aggregated body.
+const (
+	MaxMSDU       = 2304
+	MaxAMSDU7935  = 7935
+	MaxAMSDU11454 = 11454
+)
+
+// Max80211Body is the maximum frame body size.
+// Set it to MaxAMSDU7935 or MaxAMSDU11454 when encoding aggregated frames.
+var Max80211Body = MaxMSDU
 
+// Marshal serializes the frame into a newly allocated buffer, see MarshalTo.
 func (f *Frame80211) Marshal() []byte {
-	b := frame80211Pool.Get().([]byte)
-	defer frame80211Pool.Put(b)
+	b := make([]byte, f.Size())
+	n, _ := f.MarshalTo(b)
+	return b[:n]
+}
+
+// MarshalTo serializes the frame into dst and returns the number of bytes written.
+// Returns io.ErrShortBuffer if dst is shorter than Size().
+func (f *Frame80211) MarshalTo(dst []byte) (int, error) {
+	if len(dst) < f.Size() {
+		return 0, io.ErrShortBuffer
+	}
+	return len(f.appendTo(dst[:0])), nil
+}
 
-	b = b[:0]
+// appendTo appends the serialized frame to b.
+func (f *Frame80211) appendTo(b []byte) []byte {
 	b = append(b,
 		byte(f.fc>>8),
 		byte(f.fc),
@@ -170,32 +381,45 @@ func (f *Frame80211) Marshal() []byte {
 		byte(f.duration),
 	)
 	b = append(b, f.addr1[:]...)
+	if f.Type() == Control {
+		if f.controlAddrs() == 2 {
+			b = append(b, f.addr2[:]...)
+		}
+		if f.HasBody() {
+			b = append(b, f.payload...)
+		}
+		return f.appendFCS(b)
+	}
 	b = append(b, f.addr2[:]...)
 	b = append(b, f.addr3[:]...)
-	if f.sc != 0 {
-		b = append(b,
-			byte(f.sc>>8),
-			byte(f.sc),
-		)
-	}
-	if !f.addr4.IsEmpty() {
+	b = append(b,
+		byte(f.sc>>8),
+		byte(f.sc),
+	)
+	if f.IsWDS() {
 		b = append(b, f.addr4[:]...)
 	}
-	if f.qos != 0 {
+	if f.HasQoS() {
 		b = append(b,
 			byte(f.qos>>8),
 			byte(f.qos),
 		)
 	}
-	if f.htc != 0 {
+	if f.HasHTC() {
 		b = append(b, byte(f.htc>>24),
 			byte(f.htc>>16),
 			byte(f.htc>>8),
 			byte(f.htc),
 		)
 	}
-	b = append(b, f.payload...)
+	if f.HasBody() {
+		b = append(b, f.payload...)
+	}
+	return f.appendFCS(b)
+}
 
+// appendFCS computes the FCS of the encoded frame b and appends it.
+func (f *Frame80211) appendFCS(b []byte) []byte {
 	sum := crc32.ChecksumIEEE(b[:])
 	f.fcs = [4]byte{
 		byte(sum >> 24),
@@ -203,16 +427,19 @@ func (f *Frame80211) Marshal() []byte {
 		byte(sum >> 8),
 		byte(sum),
 	}
-	b = append(b, f.fcs[:]...)
-
-	return b
+	return append(b, f.fcs[:]...)
 }
 
+// Unmarshal80211 decodes a sequence of bytes into a Frame80211.
+// The fourth address is decoded only in WDS frames, the QoS Control
+// only in QoS data frames, the HT Control only if HasHTC is true. Control frames are decoded as RA and TA
+// (absent in CTS, ACK and Control Wrapper) followed by the body and FCS,
+// the control frames without body must have the exact size. The payload
+// is decoded only if the frame has a body (see HasBody).
 func Unmarshal80211(b []byte) (*Frame80211, error) {
 	f := new(Frame80211)
 	sz := len(b)
-	pSz := len(f.payload)
-	if sz < min80211Size {
+	if sz < 4 {
 		return nil, io.ErrUnexpectedEOF
 	}
 
@@ -220,6 +447,26 @@ func Unmarshal80211(b []byte) (*Frame80211, error) {
 	f.fc = binary.BigEndian.Uint16(b[0:2])
 	f.duration = binary.BigEndian.Uint16(b[2:4])
 	n += 4
+	if f.Type() == Control {
+		hdr := n + 6*f.controlAddrs() + 4
+		if sz < hdr || !f.HasBody() && sz != hdr {
+			return nil, io.ErrUnexpectedEOF
+		}
+		copy(f.addr1[:], b[n:n+6])
+		n += 6
+		if f.controlAddrs() == 2 {
+			copy(f.addr2[:], b[n:n+6])
+			n += 6
+		}
+		if f.HasBody() {
+			f.payload = b[n : sz-4]
+		}
+		copy(f.fcs[:], b[sz-4:])
+		return f, nil
+	}
+	if sz < min80211Size {
+		return nil, io.ErrUnexpectedEOF
+	}
 	copy(f.addr1[:], b[n:n+6])
 	n += 6
 	copy(f.addr2[:], b[n:n+6])
@@ -228,10 +475,67 @@ func Unmarshal80211(b []byte) (*Frame80211, error) {
 	n += 6
 	f.sc = binary.BigEndian.Uint16(b[n : n+2])
 	n += 2
-	copy(f.addr4[:], b[n:n+6])
-	n += 6
-	f.payload = b[n : sz-4]
-	n += pSz // + payload size
-	copy(f.fcs[:], b[n:])
+	if f.IsWDS() {
+		if sz < n+6+4 {
+			return nil, io.ErrUnexpectedEOF
+		}
+		copy(f.addr4[:], b[n:n+6])
+		n += 6
+	}
+	if f.HasQoS() {
+		if sz < n+2+4 {
+			return nil, io.ErrUnexpectedEOF
+		}
+		f.qos = binary.BigEndian.Uint16(b[n : n+2])
+		n += 2
+	}
+	if f.HasHTC() {
+		if sz < n+4+4 {
+			return nil, io.ErrUnexpectedEOF
+		}
+		f.htc = binary.BigEndian.Uint32(b[n : n+4])
+		n += 4
+	}
+	if f.HasBody() {
+		f.payload = b[n : sz-4]
+	}
+	copy(f.fcs[:], b[sz-4:])
 	return f, nil
 }
+
+// ToFrame80211 converts Ethernet frame into 802.11 data frame. The EtherType is carried
+// in the LLC/SNAP header (RFC 1042) prepended to the payload, addresses are assigned
+// according to the DS bits (see NewDataFrame). The 802.1Q tag is not carried.
+func (f *Frame) ToFrame80211(bssid HardwareAddr, toDS, fromDS bool) *Frame80211 {
+	snap := EncodeSNAP(OuiRFC1042, f.etherType)
+	payload := make([]byte, 0, len(snap)+len(f.payload))
+	payload = append(payload, snap[:]...)
+	payload = append(payload, f.payload...)
+	return NewDataFrame(toDS, fromDS, bssid, f.Source(), f.Destination(), payload)
+}
+
+// ToFrame80211E is like ToFrame80211, but returns ErrBodyTooLarge if the payload with
+// the SNAP header is longer than Max80211Body, e.g. the payload of a jumbo frame.
+func (f *Frame) ToFrame80211E(bssid HardwareAddr, toDS, fromDS bool) (*Frame80211, error) {
+	if snapHeaderSize+len(f.payload) > Max80211Body {
+		return nil, ErrBodyTooLarge
+	}
+	return f.ToFrame80211(bssid, toDS, fromDS), nil
+}
+
+// ToEthernet converts 802.11 data frame with the LLC/SNAP encapsulated payload
+// (RFC 1042 or 802.1H) into Ethernet frame. Returns ErrNotDataFrame if the frame
+// is not a data frame, and ErrNoSNAP if the payload isn't SNAP encapsulated.
+func (f *Frame80211) ToEthernet() (*Frame, error) {
+	if f.Type() != Data {
+		return nil, ErrNotDataFrame
+	}
+	oui, pid, payload, err := DecodeSNAP(f.payload)
+	if err != nil {
+		return nil, err
+	}
+	if oui != OuiRFC1042 && oui != Oui8021H {
+		return nil, ErrNoSNAP
+	}
+	return NewFrame(f.Source(), f.Destination(), pid, payload), nil
+}
@@ -1,6 +1,9 @@
 package ethernet
 
 import (
+	"bytes"
+	"io"
+	"sync"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -17,7 +20,6 @@ func TestFrame80211Marshal(t *testing.T) {
 		duration uint16
 		qos      uint16
 		ht       uint32
-		sc       uint16
 		tag8021q *Tag8021Q
 		payload  []byte
 		wantLen  int
@@ -29,10 +31,10 @@ func TestFrame80211Marshal(t *testing.T) {
 			addr1:    HardwareAddr{127, 127, 127, 50, 50, 50},
 			addr2:    HardwareAddr{255, 255, 255, 50, 50, 50},
 			addr3:    HardwareAddr{255, 255, 255, 50, 50, 20},
-			fc:       0x16,
+			fc:       0x08,
 			duration: 0x10,
 			payload:  []byte("HELLO"),
-			wantLen:  26 + 5,
+			wantLen:  28 + 5,
 		},
 		{
 			name:     "positive_4addr",
@@ -40,50 +42,91 @@ func TestFrame80211Marshal(t *testing.T) {
 			addr2:    HardwareAddr{255, 255, 255, 50, 50, 50},
 			addr3:    HardwareAddr{255, 255, 255, 50, 50, 20},
 			addr4:    &HardwareAddr{255, 255, 255, 10, 10, 10},
-			fc:       0x16,
+			fc:       0x308,
+			duration: 0x10,
+			payload:  []byte("HELLO"),
+			wantLen:  34 + 5,
+		},
+		{
+			name:     "positive_qos",
+			addr1:    HardwareAddr{127, 127, 127, 50, 50, 50},
+			addr2:    HardwareAddr{255, 255, 255, 50, 50, 50},
+			addr3:    HardwareAddr{255, 255, 255, 50, 50, 20},
+			fc:       0x88,
+			duration: 0x10,
+			qos:      0x4,
+			payload:  []byte("HELLO"),
+			wantLen:  30 + 5,
+		},
+		{
+			name:     "positive_ht",
+			addr1:    HardwareAddr{127, 127, 127, 50, 50, 50},
+			addr2:    HardwareAddr{255, 255, 255, 50, 50, 50},
+			addr3:    HardwareAddr{255, 255, 255, 50, 50, 20},
+			fc:       Encode80211Fc(0, uint16(Data), SubtypeQosData, 0, 0, 0, 0, 0, 0, 0, 1),
 			duration: 0x10,
+			ht:       0x1222,
 			payload:  []byte("HELLO"),
-			wantLen:  32 + 5,
+			wantLen:  34 + 5,
 		},
 		{
-			name:     "positive_sc",
+			name:     "positive_ht_without_order",
 			addr1:    HardwareAddr{127, 127, 127, 50, 50, 50},
 			addr2:    HardwareAddr{255, 255, 255, 50, 50, 50},
 			addr3:    HardwareAddr{255, 255, 255, 50, 50, 20},
-			fc:       0x16,
+			fc:       0x08,
 			duration: 0x10,
-			sc:       0x180,
+			ht:       0x1222,
 			payload:  []byte("HELLO"),
 			wantLen:  28 + 5,
 		},
 		{
-			name:     "positive_qos",
+			name:     "positive_qos_data_zero_qos",
+			addr1:    HardwareAddr{127, 127, 127, 50, 50, 50},
+			addr2:    HardwareAddr{255, 255, 255, 50, 50, 50},
+			addr3:    HardwareAddr{255, 255, 255, 50, 50, 20},
+			fc:       Encode80211Fc(0, uint16(Data), SubtypeQosData, 0, 0, 0, 0, 0, 0, 0, 0),
+			duration: 0x10,
+			payload:  []byte("HELLO"),
+			wantLen:  30 + 5,
+		},
+		{
+			name:     "positive_non_qos_with_qos_value",
 			addr1:    HardwareAddr{127, 127, 127, 50, 50, 50},
 			addr2:    HardwareAddr{255, 255, 255, 50, 50, 50},
 			addr3:    HardwareAddr{255, 255, 255, 50, 50, 20},
-			fc:       0x16,
+			fc:       Encode80211Fc(0, uint16(Data), SubtypeData, 0, 0, 0, 0, 0, 0, 0, 0),
 			duration: 0x10,
 			qos:      0x4,
 			payload:  []byte("HELLO"),
 			wantLen:  28 + 5,
 		},
 		{
-			name:     "positive_ht",
+			name:     "positive_wds_zero_addr4",
 			addr1:    HardwareAddr{127, 127, 127, 50, 50, 50},
 			addr2:    HardwareAddr{255, 255, 255, 50, 50, 50},
 			addr3:    HardwareAddr{255, 255, 255, 50, 50, 20},
-			fc:       0x16,
+			addr4:    &HardwareAddr{},
+			fc:       0x308,
 			duration: 0x10,
-			ht:       0x1222,
 			payload:  []byte("HELLO"),
-			wantLen:  30 + 5,
+			wantLen:  34 + 5,
+		},
+		{
+			name:     "positive_non_wds_no_addr4",
+			addr1:    HardwareAddr{127, 127, 127, 50, 50, 50},
+			addr2:    HardwareAddr{255, 255, 255, 50, 50, 50},
+			addr3:    HardwareAddr{255, 255, 255, 50, 50, 20},
+			fc:       0x108,
+			duration: 0x10,
+			payload:  []byte("HELLO"),
+			wantLen:  28 + 5,
 		},
 	}
 
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
 			f := NewFrame80211(tc.addr1, tc.addr2, tc.addr3, tc.addr4, tc.fc, tc.duration, tc.payload)
-			f.SetSC(tc.sc)
 			f.SetHT(tc.ht)
 			f.SetQOS(tc.qos)
 
@@ -92,10 +135,603 @@ func TestFrame80211Marshal(t *testing.T) {
 			assert.Len(t, b, tc.wantLen, "mismatched encoded frame size")
 			assert.Equal(t, tc.wantLen, f.Size())
 			//assert.Equal(t, f.Size(), tc.wantLen, "mismatched frame size")
+			if f.Type() == Control {
+				return
+			}
+
+			decoded, err := Unmarshal80211(b)
+			if !assert.NoError(t, err) {
+				return
+			}
+			assert.Equal(t, tc.payload, decoded.Payload())
+			if f.HasHTC() {
+				assert.Equal(t, tc.ht, decoded.HT())
+			} else {
+				assert.Zero(t, decoded.HT())
+			}
+		})
+	}
+}
+
+func TestNewFrame80211Addr4(t *testing.T) {
+	addr4 := HardwareAddr{255, 255, 255, 10, 10, 10}
+	_, err := NewFrame80211E(HardwareAddr{}, HardwareAddr{}, HardwareAddr{}, &addr4, 0x108, 0, nil)
+	assert.Equal(t, ErrAddr4NotWDS, err)
+	// addr4 of a non-WDS frame is not encoded
+	nf := NewFrame80211(HardwareAddr{}, HardwareAddr{}, HardwareAddr{}, &addr4, 0x208, 0, nil)
+	assert.Equal(t, min80211Size, nf.Size())
+
+	f, err := NewFrame80211E(HardwareAddr{}, HardwareAddr{}, HardwareAddr{}, &HardwareAddr{}, 0x308, 0, []byte("HELLO"))
+	assert.NoError(t, err)
+	b := f.Marshal()
+
+	decoded, err := Unmarshal80211(b)
+	assert.NoError(t, err)
+	assert.True(t, decoded.IsWDS())
+	assert.Equal(t, HardwareAddr{}, decoded.Source())
+	assert.Equal(t, []byte("HELLO"), decoded.Payload())
+	assert.Equal(t, f.FCS(), decoded.FCS())
+}
+
+func TestUnmarshal80211QoS(t *testing.T) {
+	fc := Encode80211Fc(0, uint16(Data), SubtypeQosData, 0, 0, 0, 0, 0, 0, 0, 0)
+	f := NewFrame80211(HardwareAddr{127, 127, 127, 50, 50, 50}, HardwareAddr{255, 255, 255, 50, 50, 50}, HardwareAddr{255, 255, 255, 50, 50, 20}, nil, fc, 0x10, []byte("HELLO"))
+	f.SetQOS(0x5)
+
+	decoded, err := Unmarshal80211(f.Marshal())
+	assert.NoError(t, err)
+	assert.True(t, decoded.HasQoS())
+	assert.Equal(t, uint16(0x5), decoded.QOS())
+	assert.Equal(t, []byte("HELLO"), decoded.Payload())
+}
+
+func TestNewDataFrame(t *testing.T) {
+	type suite struct {
+		name            string
+		toDS            bool
+		fromDS          bool
+		wantReceiver    HardwareAddr
+		wantTransmitter HardwareAddr
+	}
+
+	bssid := HardwareAddr{0x10, 0x10, 0x10, 0x10, 0x10, 0x10}
+	src := HardwareAddr{0x20, 0x20, 0x20, 0x20, 0x20, 0x20}
+	dst := HardwareAddr{0x30, 0x30, 0x30, 0x30, 0x30, 0x30}
+
+	testCases := []suite{
+		{
+			name:            "positive_ap_to_sta",
+			fromDS:          true,
+			wantReceiver:    dst,
+			wantTransmitter: bssid,
+		},
+		{
+			name:            "positive_sta_to_ap",
+			toDS:            true,
+			wantReceiver:    bssid,
+			wantTransmitter: src,
+		},
+		{
+			name:            "positive_adhoc",
+			wantReceiver:    dst,
+			wantTransmitter: src,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			f := NewDataFrame(tc.toDS, tc.fromDS, bssid, src, dst, []byte("HELLO"))
+			fc := Decode80211Fc(f.FrameControl())
+			assert.Equal(t, uint16(Data), fc[1], "frame type mismatch")
+			assert.Equal(t, uint16(SubtypeData), fc[2], "subtype mismatch")
+			assert.Equal(t, tc.wantReceiver, f.Receiver())
+			assert.Equal(t, tc.wantTransmitter, f.Transmitter())
+			assert.Equal(t, src, f.Source())
+			assert.Equal(t, dst, f.Destination())
+		})
+	}
+}
+
+func TestFrame80211MarshalAggregated(t *testing.T) {
+	defer func(v int) { Max80211Body = v }(Max80211Body)
+
+	for _, max := range []int{MaxMSDU, MaxAMSDU7935, MaxAMSDU11454} {
+		Max80211Body = max
+		f := NewFrame80211(HardwareAddr{127, 127, 127, 50, 50, 50}, HardwareAddr{255, 255, 255, 50, 50, 50}, HardwareAddr{255, 255, 255, 50, 50, 20}, nil, 0x08, 0x10, make([]byte, max))
+		b := f.Marshal()
+		assert.Len(t, b, f.Size())
+		assert.Equal(t, 28+max, f.Size())
+	}
+}
+
+func TestNewFrame80211BodyTooLarge(t *testing.T) {
+	defer func(v int) { Max80211Body = v }(Max80211Body)
+	fc := Encode80211Fc(0, uint16(Data), SubtypeData, 0, 0, 0, 0, 0, 0, 0, 0)
+
+	_, err := NewFrame80211E(HardwareAddr{}, HardwareAddr{}, HardwareAddr{}, nil, fc, 0, make([]byte, MaxMSDU))
+	assert.NoError(t, err)
+	_, err = NewFrame80211E(HardwareAddr{}, HardwareAddr{}, HardwareAddr{}, nil, fc, 0, make([]byte, MaxMSDU+1))
+	assert.Equal(t, ErrBodyTooLarge, err)
+	// the size is checked only by the E constructors
+	f := NewFrame80211(HardwareAddr{}, HardwareAddr{}, HardwareAddr{}, nil, fc, 0, make([]byte, MaxMSDU+1))
+	assert.Len(t, f.Payload(), MaxMSDU+1)
+
+	// aggregated body is accepted with the increased limit
+	Max80211Body = MaxAMSDU7935
+	_, err = NewFrame80211E(HardwareAddr{}, HardwareAddr{}, HardwareAddr{}, nil, fc, 0, make([]byte, MaxMSDU+1))
+	assert.NoError(t, err)
+}
+
+func TestFrameToFrame80211RoundTrip(t *testing.T) {
+	type suite struct {
+		name   string
+		toDS   bool
+		fromDS bool
+	}
+
+	testCases := []suite{
+		{name: "positive_ap_to_sta", fromDS: true},
+		{name: "positive_sta_to_ap", toDS: true},
+		{name: "positive_adhoc"},
+	}
+
+	bssid := HardwareAddr{0x10, 0x10, 0x10, 0x10, 0x10, 0x10}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			f := NewFrame(HardwareAddr{127, 127, 127, 50, 50, 50}, HardwareAddr{255, 255, 255, 50, 50, 50}, EtherTypeIPv6, generatePayload())
+			wf := f.ToFrame80211(bssid, tc.toDS, tc.fromDS)
+			assert.Equal(t, Data, wf.Type())
+			assert.Equal(t, f.Source(), wf.Source())
+			assert.Equal(t, f.Destination(), wf.Destination())
+			assert.Len(t, wf.Payload(), len(f.Payload())+8)
+
+			// through the wire format
+			received, err := Unmarshal80211(wf.Marshal())
+			if !assert.NoError(t, err) {
+				return
+			}
+			assert.Equal(t, wf.Payload(), received.Payload())
+			decoded, err := received.ToEthernet()
+			if !assert.NoError(t, err) {
+				return
+			}
+			assert.Equal(t, f.Source(), decoded.Source())
+			assert.Equal(t, f.Destination(), decoded.Destination())
+			assert.Equal(t, f.EtherType(), decoded.EtherType())
+			assert.Equal(t, f.Payload(), decoded.Payload())
+		})
+	}
+}
+
+func TestFrameToFrame80211Jumbo(t *testing.T) {
+	src := HardwareAddr{127, 127, 127, 50, 50, 50}
+	dst := HardwareAddr{255, 255, 255, 50, 50, 50}
+	bssid := HardwareAddr{0x10, 0x10, 0x10, 0x10, 0x10, 0x10}
+
+	type suite struct {
+		name    string
+		size    int
+		wantErr error
+	}
+
+	testCases := []suite{
+		{name: "positive_max_body", size: Max80211Body - 8},
+		{name: "negative_jumbo", size: 9000, wantErr: ErrBodyTooLarge},
+		{name: "negative_above_max_body", size: Max80211Body - 7, wantErr: ErrBodyTooLarge},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			f := NewFrame(src, dst, EtherTypeIPv4, make([]byte, tc.size))
+			wf, err := f.ToFrame80211E(bssid, true, false)
+			assert.Equal(t, tc.wantErr, err)
+			if tc.wantErr != nil {
+				assert.Nil(t, wf)
+				// the jumbo frame is converted without the size check
+				assert.Len(t, f.ToFrame80211(bssid, true, false).Payload(), tc.size+8)
+				return
+			}
+			assert.Len(t, wf.Payload(), Max80211Body)
+		})
+	}
+
+	_, err := NewDataFrameE(false, false, bssid, src, dst, make([]byte, Max80211Body+1))
+	assert.Equal(t, ErrBodyTooLarge, err)
+}
+
+func TestNewDataFrameUnmarshal(t *testing.T) {
+	bssid := HardwareAddr{0x10, 0x10, 0x10, 0x10, 0x10, 0x10}
+	src := HardwareAddr{127, 127, 127, 50, 50, 50}
+	dst := HardwareAddr{255, 255, 255, 50, 50, 50}
+
+	for _, ds := range [][2]bool{{false, false}, {false, true}, {true, false}, {true, true}} {
+		f := NewDataFrame(ds[0], ds[1], bssid, src, dst, []byte("0123456789"))
+		decoded, err := Unmarshal80211(f.Marshal())
+		if !assert.NoError(t, err) {
+			continue
+		}
+		assert.Equal(t, []byte("0123456789"), decoded.Payload())
+		assert.Equal(t, src, decoded.Source())
+		assert.Equal(t, dst, decoded.Destination())
+	}
+}
+
+func TestFrame80211ToEthernetErrors(t *testing.T) {
+	fc := Encode80211Fc(0, uint16(Management), SubtypeBeacon, 0, 0, 0, 0, 0, 0, 0, 0)
+	f := NewFrame80211(HardwareAddr{}, HardwareAddr{}, HardwareAddr{}, nil, fc, 0, nil)
+	_, err := f.ToEthernet()
+	assert.Equal(t, ErrNotDataFrame, err)
+
+	f = NewDataFrame(false, false, HardwareAddr{}, HardwareAddr{}, HardwareAddr{}, []byte("HELLO, WORLD"))
+	_, err = f.ToEthernet()
+	assert.Equal(t, ErrNoSNAP, err)
+}
+
+func TestFrame80211CipherOverhead(t *testing.T) {
+	type suite struct {
+		name       string
+		wep        uint16
+		cipher     Cipher
+		wantPrefix int
+		wantSuffix int
+	}
+
+	testCases := []suite{
+		{name: "positive_ccmp", wep: 1, cipher: CipherCCMP, wantPrefix: 8, wantSuffix: 8},
+		{name: "positive_tkip", wep: 1, cipher: CipherTKIP, wantPrefix: 8, wantSuffix: 12},
+		{name: "positive_wep", wep: 1, cipher: CipherWEP, wantPrefix: 4, wantSuffix: 4},
+		{name: "positive_unprotected", cipher: CipherCCMP},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			fc := Encode80211Fc(0, uint16(Data), SubtypeData, 1, 0, 0, 0, 0, 0, tc.wep, 0)
+			f := NewFrame80211(HardwareAddr{}, HardwareAddr{}, HardwareAddr{}, nil, fc, 0, make([]byte, 64))
+			assert.Equal(t, tc.wep == 1, f.Protected())
+			prefix, suffix := f.CipherOverhead(tc.cipher)
+			assert.Equal(t, tc.wantPrefix, prefix)
+			assert.Equal(t, tc.wantSuffix, suffix)
+		})
+	}
+}
+
+func TestFrame80211DurationID(t *testing.T) {
+	type suite struct {
+		name      string
+		fc        uint16
+		duration  uint16
+		wantNav   uint16
+		wantAID   uint16
+		wantIsAID bool
+	}
+
+	testCases := []suite{
+		{
+			name:      "positive_ps_poll",
+			fc:        Encode80211Fc(0, uint16(Control), SubtypePsPoll, 0, 0, 0, 0, 0, 0, 0, 0),
+			duration:  0xC000 | 42,
+			wantAID:   42,
+			wantIsAID: true,
+		},
+		{
+			name:     "positive_data",
+			fc:       Encode80211Fc(0, uint16(Data), SubtypeData, 1, 0, 0, 0, 0, 0, 0, 0),
+			duration: 314,
+			wantNav:  314,
+		},
+		{
+			name:     "negative_data_reserved",
+			fc:       Encode80211Fc(0, uint16(Data), SubtypeData, 1, 0, 0, 0, 0, 0, 0, 0),
+			duration: 0xC000 | 42,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			f := NewFrame80211(HardwareAddr{}, HardwareAddr{}, HardwareAddr{}, nil, tc.fc, tc.duration, nil)
+			nav, aid, isAID := f.DurationID()
+			assert.Equal(t, tc.wantNav, nav)
+			assert.Equal(t, tc.wantAID, aid)
+			assert.Equal(t, tc.wantIsAID, isAID)
+		})
+	}
+}
+
+func TestNewPSPoll(t *testing.T) {
+	bssid := HardwareAddr{1, 1, 1, 1, 1, 1}
+	ta := HardwareAddr{127, 127, 127, 50, 50, 50}
+
+	f := NewPSPoll(42, bssid, ta)
+	b := f.Marshal()
+	assert.Equal(t, 2+2+6+6+4, len(b), "PS-Poll is FC + AID + BSSID + TA + FCS")
+
+	decoded, err := Unmarshal80211(b)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, Control, decoded.Type())
+	assert.Equal(t, uint16(SubtypePsPoll), decoded.Subtype())
+	assert.Equal(t, bssid, decoded.Receiver())
+	assert.Equal(t, ta, decoded.Transmitter())
+	assert.False(t, decoded.HasBody())
+	assert.Nil(t, decoded.Payload())
+	assert.Equal(t, f.FCS(), decoded.FCS())
+
+	nav, aid, isAID := decoded.DurationID()
+	assert.Equal(t, uint16(0), nav)
+	assert.Equal(t, uint16(42), aid)
+	assert.True(t, isAID)
+
+	_, aid, _ = NewPSPoll(0xC000|7, bssid, ta).DurationID()
+	assert.Equal(t, uint16(7), aid, "bits above AID are ignored")
+}
+
+func TestNewCTSToSelf(t *testing.T) {
+	ra := HardwareAddr{127, 127, 127, 50, 50, 50}
+
+	f := NewCTSToSelf(314, ra)
+	assert.Equal(t, 14, f.Size())
+	b := f.Marshal()
+	assert.Equal(t, f.Size(), len(b))
+
+	decoded, err := Unmarshal80211(b)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, Control, decoded.Type())
+	assert.Equal(t, uint16(SubtypeCts), decoded.Subtype())
+	assert.Equal(t, ra, decoded.Receiver())
+	assert.Equal(t, HardwareAddr{}, decoded.Transmitter())
+	assert.Equal(t, uint16(314), decoded.Duration())
+	assert.Equal(t, f.FCS(), decoded.FCS())
+	assert.Equal(t, b, decoded.Marshal())
+}
+
+func TestFrame80211HasBody(t *testing.T) {
+	type suite struct {
+		name     string
+		fc       uint16
+		wantBody bool
+		wantLen  int
+	}
+
+	testCases := []suite{
+		{
+			name:     "positive_management_beacon",
+			fc:       Encode80211Fc(0, uint16(Management), SubtypeBeacon, 0, 0, 0, 0, 0, 0, 0, 0),
+			wantBody: true,
+			wantLen:  28 + 5,
+		},
+		{
+			name:     "positive_data",
+			fc:       Encode80211Fc(0, uint16(Data), SubtypeData, 0, 0, 0, 0, 0, 0, 0, 0),
+			wantBody: true,
+			wantLen:  28 + 5,
+		},
+		{
+			name:     "positive_qos_data",
+			fc:       Encode80211Fc(0, uint16(Data), SubtypeQosData, 0, 0, 0, 0, 0, 0, 0, 0),
+			wantBody: true,
+			wantLen:  30 + 5,
+		},
+		{
+			name:    "negative_data_null",
+			fc:      Encode80211Fc(0, uint16(Data), 0x4, 0, 0, 0, 0, 0, 0, 0, 0),
+			wantLen: 28,
+		},
+		{
+			name:    "negative_qos_null",
+			fc:      Encode80211Fc(0, uint16(Data), 0xC, 0, 0, 0, 0, 0, 0, 0, 0),
+			wantLen: 30,
+		},
+		{
+			name:    "negative_control_rts",
+			fc:      Encode80211Fc(0, uint16(Control), SubtypeRts, 0, 0, 0, 0, 0, 0, 0, 0),
+			wantLen: 20,
+		},
+		{
+			name:    "negative_control_ps_poll",
+			fc:      Encode80211Fc(0, uint16(Control), SubtypePsPoll, 0, 0, 0, 0, 0, 0, 0, 0),
+			wantLen: 20,
+		},
+		{
+			name:    "negative_control_cts",
+			fc:      Encode80211Fc(0, uint16(Control), SubtypeCts, 0, 0, 0, 0, 0, 0, 0, 0),
+			wantLen: 14,
+		},
+		{
+			name:    "negative_control_ack",
+			fc:      Encode80211Fc(0, uint16(Control), SubtypeAck, 0, 0, 0, 0, 0, 0, 0, 0),
+			wantLen: 14,
+		},
+		{
+			name:    "negative_control_cf_end",
+			fc:      Encode80211Fc(0, uint16(Control), SubtypeCfEnd, 0, 0, 0, 0, 0, 0, 0, 0),
+			wantLen: 20,
+		},
+		{
+			name:     "positive_control_block_ack_req",
+			fc:       Encode80211Fc(0, uint16(Control), SubtypeBlockAckReq, 0, 0, 0, 0, 0, 0, 0, 0),
+			wantBody: true,
+			wantLen:  20 + 5,
+		},
+		{
+			name:     "positive_control_trigger",
+			fc:       Encode80211Fc(0, uint16(Control), SubtypeTrigger, 0, 0, 0, 0, 0, 0, 0, 0),
+			wantBody: true,
+			wantLen:  20 + 5,
+		},
+		{
+			name:     "positive_control_wrapper",
+			fc:       Encode80211Fc(0, uint16(Control), SubtypeControlWrapper, 0, 0, 0, 0, 0, 0, 0, 0),
+			wantBody: true,
+			wantLen:  14 + 5,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ra := HardwareAddr{127, 127, 127, 50, 50, 50}
+			ta := HardwareAddr{255, 255, 255, 50, 50, 50}
+			f := NewFrame80211(ra, ta, HardwareAddr{255, 255, 255, 50, 50, 20}, nil, tc.fc, 0x10, []byte("HELLO"))
+			assert.Equal(t, tc.wantBody, f.HasBody())
+
+			b := f.Marshal()
+			assert.Len(t, b, tc.wantLen)
+			assert.Equal(t, tc.wantLen, f.Size())
+
+			decoded, err := Unmarshal80211(b)
+			if !assert.NoError(t, err) {
+				return
+			}
+			assert.Equal(t, tc.wantBody, decoded.HasBody())
+			assert.Equal(t, ra, decoded.Receiver())
+			assert.Equal(t, f.FCS(), decoded.FCS())
+			if tc.wantBody {
+				assert.Equal(t, []byte("HELLO"), decoded.Payload())
+			} else {
+				assert.Empty(t, decoded.Payload())
+			}
+			if f.Type() == Control && f.controlAddrs() == 1 {
+				assert.Equal(t, HardwareAddr{}, decoded.Transmitter())
+			} else {
+				assert.Equal(t, ta, decoded.Transmitter())
+			}
 		})
 	}
 }
 
+func TestUnmarshal80211BlockAck(t *testing.T) {
+	ra := HardwareAddr{0x00, 0x1B, 0x21, 0x6D, 0x7E, 0x8F}
+	ta := HardwareAddr{0x00, 0x1B, 0x21, 0x3A, 0x4B, 0x5C}
+
+	type suite struct {
+		name    string
+		subtype uint16
+		body    []byte
+	}
+
+	testCases := []suite{
+		{
+			// BAR Control (compressed bitmap, TID 0) + Starting Sequence Control (SN 100)
+			name:    "positive_block_ack_req",
+			subtype: SubtypeBlockAckReq,
+			body:    []byte{0x04, 0x00, 0x40, 0x06},
+		},
+		{
+			// BA Control (compressed bitmap, TID 0) + Starting Sequence Control (SN 100) + bitmap
+			name:    "positive_block_ack",
+			subtype: SubtypeBlockAck,
+			body:    []byte{0x05, 0x00, 0x40, 0x06, 0xFF, 0xFF, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			fc := Encode80211Fc(0, uint16(Control), tc.subtype, 0, 0, 0, 0, 0, 0, 0, 0)
+			b := NewFrame80211(ra, ta, HardwareAddr{}, nil, fc, 60, tc.body).Marshal()
+			assert.Len(t, b, 2+2+6+6+len(tc.body)+4)
+			assert.True(t, VerifyFCS(b))
+
+			decoded, err := Unmarshal80211(b)
+			if !assert.NoError(t, err) {
+				return
+			}
+			assert.Equal(t, Control, decoded.Type())
+			assert.Equal(t, tc.subtype, decoded.Subtype())
+			assert.True(t, decoded.HasBody())
+			assert.Equal(t, ra, decoded.Receiver())
+			assert.Equal(t, ta, decoded.Transmitter())
+			assert.Equal(t, uint16(60), decoded.Duration())
+			assert.Equal(t, tc.body, decoded.Payload())
+			assert.Equal(t, b, decoded.Marshal())
+
+			// RA and TA are mandatory
+			_, err = Unmarshal80211(b[:2+2+6+4])
+			assert.Equal(t, io.ErrUnexpectedEOF, err)
+		})
+	}
+}
+
+func TestUnmarshal80211ControlTruncated(t *testing.T) {
+	f := NewFrame80211(HardwareAddr{1, 2, 3, 4, 5, 6}, HardwareAddr{}, HardwareAddr{}, nil, Encode80211Fc(0, uint16(Control), SubtypeAck, 0, 0, 0, 0, 0, 0, 0, 0), 0, nil)
+	b := f.Marshal()
+	_, err := Unmarshal80211(b[:len(b)-1])
+	assert.Equal(t, io.ErrUnexpectedEOF, err)
+	_, err = Unmarshal80211(b[:3])
+	assert.Equal(t, io.ErrUnexpectedEOF, err)
+
+	// the control frames without body have the exact size
+	_, err = Unmarshal80211(append(append([]byte(nil), b...), 0))
+	assert.Equal(t, io.ErrUnexpectedEOF, err)
+}
+
+func TestFrame80211MarshalTo(t *testing.T) {
+	f := NewDataFrame(true, false, HardwareAddr{1, 1, 1, 1, 1, 1}, HardwareAddr{127, 127, 127, 50, 50, 50}, HardwareAddr{255, 255, 255, 50, 50, 50}, []byte("HELLO"))
+	want := f.Marshal()
+
+	dst := make([]byte, f.Size()+10)
+	n, err := f.MarshalTo(dst)
+	assert.NoError(t, err)
+	assert.Equal(t, want, dst[:n])
+
+	n, err = f.MarshalTo(dst[:f.Size()-1])
+	assert.Equal(t, io.ErrShortBuffer, err)
+	assert.Zero(t, n)
+}
+
+func TestFrame80211MarshalToConcurrent(t *testing.T) {
+	var wg sync.WaitGroup
+	for i := 0; i < 16; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			payload := bytes.Repeat([]byte{byte(i)}, 100+i)
+			f := NewDataFrame(false, true, HardwareAddr{1, 1, 1, 1, 1, byte(i)}, HardwareAddr{2, 2, 2, 2, 2, byte(i)}, HardwareAddr{3, 3, 3, 3, 3, byte(i)}, payload)
+			dst := make([]byte, f.Size())
+			for j := 0; j < 100; j++ {
+				n, err := f.MarshalTo(dst)
+				assert.NoError(t, err)
+				decoded, err := Unmarshal80211(dst[:n])
+				assert.NoError(t, err)
+				assert.Equal(t, payload, decoded.Payload())
+			}
+		}(i)
+	}
+	wg.Wait()
+}
+
+func TestFrame80211MarshalConcurrent(t *testing.T) {
+	const n = 16
+	frames := make([]*Frame80211, n)
+	want := make([][]byte, n)
+	for i := range frames {
+		frames[i] = NewDataFrame(false, false, HardwareAddr{1, 1, 1, 1, 1, byte(i)}, HardwareAddr{2, 2, 2, 2, 2, byte(i)}, HardwareAddr{3, 3, 3, 3, 3, byte(i)}, bytes.Repeat([]byte{byte(i)}, 64))
+		want[i] = append([]byte(nil), frames[i].Marshal()...)
+	}
+
+	// the results are retained until all goroutines finish, so a buffer shared
+	// between Marshal calls would be overwritten by other frames
+	got := make([][][]byte, n)
+	var wg sync.WaitGroup
+	for i := range frames {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 50; j++ {
+				got[i] = append(got[i], frames[i].Marshal())
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	for i := range got {
+		for _, b := range got[i] {
+			assert.Equal(t, want[i], b)
+		}
+	}
+}
+
 func BenchmarkFrame80211Marshal(b *testing.B) {
 	payload := generatePayload()
 	b.ResetTimer()
@@ -1,8 +1,16 @@
 package ethernet
 
 import (
+	"bytes"
+	"encoding/binary"
 	"fmt"
+	"hash/crc32"
+	"io"
+	"io/ioutil"
 	"math/rand"
+	"strconv"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -70,6 +78,216 @@ func BenchmarkFrameMarshal(b *testing.B) {
 	}
 }
 
+func TestFrameMarshalCache(t *testing.T) {
+	src := HardwareAddr{127, 127, 127, 50, 50, 50}
+	dst := HardwareAddr{255, 255, 255, 50, 50, 50}
+
+	type suite struct {
+		name   string
+		modify func(f *Frame)
+	}
+
+	testCases := []suite{
+		{name: "positive_set_ethertype", modify: func(f *Frame) { f.SetEtherType(EtherTypeIPv6) }},
+		{name: "positive_set_payload", modify: func(f *Frame) { f.SetPayload(make([]byte, 50)) }},
+		{name: "positive_set_tag", modify: func(f *Frame) { f.SetTag8021Q(&Tag8021Q{TPID: uint16(EtherTypeVlan), TCI: 100}) }},
+		{name: "positive_set_inner_tags", modify: func(f *Frame) {
+			f.SetInnerTags([]Tag8021Q{{TPID: uint16(EtherTypeVlan), TCI: 100}})
+		}},
+		{name: "positive_set_fcs_keep", modify: func(f *Frame) {
+			f.SetFCSMode(FCSKeep)
+			f.SetFCS([4]byte{0xDE, 0xAD, 0xBE, 0xEF})
+		}},
+		{name: "positive_assign_vlan", modify: func(f *Frame) { f.AssignVLAN(100) }},
+		{name: "positive_remark", modify: func(f *Frame) { f.Remark(PcpVO) }},
+		{name: "positive_unmarshal", modify: func(f *Frame) {
+			Unmarshal(NewFrame(dst, src, EtherTypeARP, nil).MarshalReadOnly(), f)
+		}},
+		{name: "positive_copy_to", modify: func(f *Frame) { NewFrame(dst, src, EtherTypeARP, nil).CopyTo(f) }},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			f := NewFrame(src, dst, EtherTypeIPv4, []byte("HELLO"))
+			f.SetMarshalCache(true)
+			b1 := f.Marshal()
+			assert.Equal(t, f.MarshalReadOnly(), b1)
+			b2 := f.Marshal()
+			assert.Equal(t, &b1[0], &b2[0], "unchanged frame is not encoded again")
+
+			tc.modify(f)
+			assert.Equal(t, f.MarshalReadOnly(), f.Marshal(), "cache is invalidated")
+		})
+	}
+
+	// untracked in-place modification is visible after invalidation by setter
+	f := NewFrame(src, dst, EtherTypeIPv4, []byte("HELLO"))
+	f.SetMarshalCache(true)
+	_ = f.Marshal()
+	f.Payload()[0] = 'J'
+	f.SetPayload(f.Payload())
+	assert.Equal(t, f.MarshalReadOnly(), f.Marshal())
+}
+
+func BenchmarkFrameMarshalCache(b *testing.B) {
+	f := NewFrame(HardwareAddr{127, 127, 127, 50, 50, 50}, HardwareAddr{255, 255, 255, 50, 50, 50}, EtherTypeIPv4, generatePayload())
+
+	b.Run("fresh", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			_ = f.Marshal()
+		}
+	})
+	b.Run("cached", func(b *testing.B) {
+		f.SetMarshalCache(true)
+		defer f.SetMarshalCache(false)
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			_ = f.Marshal()
+		}
+	})
+}
+
+func TestFrameMarshalAppend(t *testing.T) {
+	f1 := NewFrame(HardwareAddr{127, 127, 127, 50, 50, 50}, HardwareAddr{255, 255, 255, 50, 50, 50}, EtherTypeIPv4, []byte("HELLO"))
+	f2 := NewFrame(HardwareAddr{127, 127, 127, 50, 50, 51}, HardwareAddr{255, 255, 255, 50, 50, 51}, EtherTypeIPv4, generatePayload())
+	want := append([]byte(nil), f1.Marshal()...)
+	want = append(want, f2.Marshal()...)
+
+	b := f1.MarshalAppend([]byte{})
+	b = f2.MarshalAppend(b)
+	assert.Equal(t, want, b)
+	assert.Len(t, b, f1.Size()+f2.Size())
+}
+
+func BenchmarkFrameMarshalAppend(b *testing.B) {
+	payload := generatePayload()
+	f := NewFrame(HardwareAddr{127, 127, 127, 50, 50, 50}, HardwareAddr{255, 255, 255, 50, 50, 50}, EtherTypeIPv4, payload)
+	dst := make([]byte, 0, 16*f.Size())
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		dst = dst[:0]
+		for j := 0; j < 16; j++ {
+			dst = f.MarshalAppend(dst)
+		}
+	}
+}
+
+func TestFrameMarshalTo(t *testing.T) {
+	f := NewFrame(HardwareAddr{127, 127, 127, 50, 50, 50}, HardwareAddr{255, 255, 255, 50, 50, 50}, EtherTypeIPv4, generatePayload(), WithVLAN(PcpVI, 0, 100))
+	f.SetInnerTags([]Tag8021Q{{TPID: uint16(EtherTypeVlan), TCI: Encode8021qTCI(PcpBE, 0, 200)}})
+	want := append([]byte(nil), f.Marshal()...)
+
+	type suite struct {
+		name    string
+		marshal func(dst []byte) (int, error)
+	}
+
+	testCases := []suite{
+		{
+			name:    "positive_marshal_to",
+			marshal: f.MarshalTo,
+		},
+		{
+			name: "positive_marshal_with_ieee",
+			marshal: func(dst []byte) (int, error) {
+				return f.MarshalWith(dst, crc32.NewIEEE())
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			dst := make([]byte, f.Size()+10)
+			n, err := tc.marshal(dst)
+			assert.NoError(t, err)
+			assert.Equal(t, want, dst[:n])
+			assert.Equal(t, crc32.ChecksumIEEE(want[:n-4]), f.FCSUint32())
+
+			n, err = tc.marshal(dst[:f.Size()-1])
+			assert.Equal(t, io.ErrShortBuffer, err)
+			assert.Zero(t, n)
+		})
+	}
+
+	// reused hash is reset between calls
+	h := crc32.NewIEEE()
+	dst := make([]byte, f.Size())
+	for i := 0; i < 2; i++ {
+		n, err := f.MarshalWith(dst, h)
+		assert.NoError(t, err)
+		assert.Equal(t, want, dst[:n])
+	}
+}
+
+func BenchmarkFrameMarshalTo(b *testing.B) {
+	f := NewFrame(HardwareAddr{127, 127, 127, 50, 50, 50}, HardwareAddr{255, 255, 255, 50, 50, 50}, EtherTypeIPv4, generatePayload())
+	dst := make([]byte, f.Size())
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		f.MarshalTo(dst)
+	}
+}
+
+func BenchmarkFrameMarshalWith(b *testing.B) {
+	f := NewFrame(HardwareAddr{127, 127, 127, 50, 50, 50}, HardwareAddr{255, 255, 255, 50, 50, 50}, EtherTypeIPv4, generatePayload())
+	dst := make([]byte, f.Size())
+	h := crc32.NewIEEE()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		f.MarshalWith(dst, h)
+	}
+}
+
+func BenchmarkFrameMarshalConcat(b *testing.B) {
+	payload := generatePayload()
+	f := NewFrame(HardwareAddr{127, 127, 127, 50, 50, 50}, HardwareAddr{255, 255, 255, 50, 50, 50}, EtherTypeIPv4, payload)
+	dst := make([]byte, 0, 16*f.Size())
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		dst = dst[:0]
+		for j := 0; j < 16; j++ {
+			dst = append(dst, f.Marshal()...)
+		}
+	}
+}
+
+func BenchmarkFCS(b *testing.B) {
+	for _, sz := range []int{MinFrameSize, MaxFrameSize, 9018} {
+		data := make([]byte, sz-4)
+		rand.Read(data)
+		b.Run(strconv.Itoa(sz), func(b *testing.B) {
+			b.SetBytes(int64(len(data)))
+			for i := 0; i < b.N; i++ {
+				_ = crc32.ChecksumIEEE(data)
+			}
+		})
+	}
+}
+
+func BenchmarkFrameMarshalJumbo(b *testing.B) {
+	payload := make([]byte, 9000)
+	rand.Read(payload)
+	f := NewFrame(HardwareAddr{127, 127, 127, 50, 50, 50}, HardwareAddr{255, 255, 255, 50, 50, 50}, EtherTypeIPv4, payload)
+	dst := make([]byte, 0, f.Size())
+	b.SetBytes(int64(f.Size()))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		dst = f.MarshalAppend(dst[:0])
+	}
+}
+
+func TestFrameFCSJumbo(t *testing.T) {
+	payload := make([]byte, 9000)
+	rand.Read(payload)
+	f := NewFrame(HardwareAddr{127, 127, 127, 50, 50, 50}, HardwareAddr{255, 255, 255, 50, 50, 50}, EtherTypeIPv4, payload)
+	b := f.MarshalAppend(nil)
+	sz := len(b)
+	assert.Equal(t, crc32.Checksum(b[:sz-4], crc32.MakeTable(crc32.IEEE)), binary.BigEndian.Uint32(b[sz-4:]))
+}
+
 func TestFrameUnmarshal(t *testing.T) {
 	type suite struct {
 		name            string
@@ -105,6 +323,18 @@ func TestFrameUnmarshal(t *testing.T) {
 	}
 }
 
+func TestFrameRawAddresses(t *testing.T) {
+	data := append([]byte(nil), NewFrame(HardwareAddr{127, 127, 127, 50, 50, 50}, HardwareAddr{255, 255, 255, 50, 50, 50}, EtherTypeIPv4, []byte("HELLO")).Marshal()...)
+	var f Frame
+	assert.NoError(t, Unmarshal(data, &f))
+
+	first, second := f.RawAddresses()
+	assert.Equal(t, data[0:6], first[:])
+	assert.Equal(t, data[6:12], second[:])
+	assert.Equal(t, f.Destination(), HardwareAddr(first))
+	assert.Equal(t, f.Source(), HardwareAddr(second))
+}
+
 func BenchmarkFrameUnmarshal(b *testing.B) {
 	payload := generatePayload()
 	data := NewFrame(HardwareAddr{127, 127, 127, 50, 50, 50}, HardwareAddr{255, 255, 255, 50, 50, 50}, EtherTypeIPv4, payload).Marshal()
@@ -116,3 +346,1033 @@ func BenchmarkFrameUnmarshal(b *testing.B) {
 		}
 	}
 }
+
+func TestFrameOffsets(t *testing.T) {
+	f := NewFrame(HardwareAddr{127, 127, 127, 50, 50, 50}, HardwareAddr{255, 255, 255, 50, 50, 50}, EtherTypeIPv6, []byte("HELLO"))
+	b := f.Marshal()
+	assert.Equal(t, f.Destination(), HardwareAddr{b[OffsetDst], b[OffsetDst+1], b[OffsetDst+2], b[OffsetDst+3], b[OffsetDst+4], b[OffsetDst+5]})
+	assert.Equal(t, f.Source(), HardwareAddr{b[OffsetSrc], b[OffsetSrc+1], b[OffsetSrc+2], b[OffsetSrc+3], b[OffsetSrc+4], b[OffsetSrc+5]})
+	off := EtherTypeOffset(false)
+	assert.Equal(t, EtherTypeIPv6, EtherType(uint16(b[off])<<8|uint16(b[off+1])))
+	assert.Equal(t, []byte("HELLO"), b[OffsetPayload:OffsetPayload+5])
+
+	f.SetTag8021Q(&Tag8021Q{TPID: uint16(EtherTypeVlan), TCI: Encode8021qTCI(PcpBE, 0, 100)})
+	b = f.Marshal()
+	assert.Equal(t, uint16(EtherTypeVlan), uint16(b[OffsetTPID])<<8|uint16(b[OffsetTPID+1]))
+	assert.Equal(t, f.Tag8021Q().TCI, uint16(b[OffsetTCI])<<8|uint16(b[OffsetTCI+1]))
+	off = EtherTypeOffset(true)
+	assert.Equal(t, EtherTypeIPv6, EtherType(uint16(b[off])<<8|uint16(b[off+1])))
+	assert.Equal(t, []byte("HELLO"), b[OffsetPayloadTagged:OffsetPayloadTagged+5])
+}
+
+func TestNewFrameOptions(t *testing.T) {
+	type suite struct {
+		name        string
+		opts        []FrameOption
+		wantErr     error
+		wantTagged  bool
+		wantPayload int
+	}
+
+	testCases := []suite{
+		{
+			name:        "positive_no_options",
+			wantPayload: minPayloadSize,
+		},
+		{
+			name:        "positive_vlan",
+			opts:        []FrameOption{WithVLAN(PcpVI, 1, 1024)},
+			wantTagged:  true,
+			wantPayload: minPayloadSize,
+		},
+		{
+			name:        "positive_raw_payload",
+			opts:        []FrameOption{WithRawPayload()},
+			wantPayload: 5,
+		},
+		{
+			name:        "positive_vlan_raw_payload",
+			opts:        []FrameOption{WithVLAN(PcpBE, 0, 1), WithRawPayload()},
+			wantTagged:  true,
+			wantPayload: 5,
+		},
+		{
+			name:        "positive_min_payload_zero",
+			opts:        []FrameOption{WithMinPayload(0)},
+			wantPayload: 5,
+		},
+		{
+			name:        "positive_min_payload_20",
+			opts:        []FrameOption{WithMinPayload(20)},
+			wantPayload: 20,
+		},
+		{
+			name:    "negative_min_payload",
+			opts:    []FrameOption{WithMinPayload(-1)},
+			wantErr: ErrInvalidMinPayload,
+		},
+		{
+			name:    "negative_vlan_pcp",
+			opts:    []FrameOption{WithVLAN(PcpNC+1, 0, 1)},
+			wantErr: ErrInvalidPCP,
+		},
+		{
+			name:    "negative_vlan_dei",
+			opts:    []FrameOption{WithVLAN(PcpBE, 2, 1)},
+			wantErr: ErrInvalidDEI,
+		},
+		{
+			name:    "negative_vlan_id",
+			opts:    []FrameOption{WithVLAN(PcpBE, 0, 4096)},
+			wantErr: ErrInvalidVLAN,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			src := HardwareAddr{127, 127, 127, 50, 50, 50}
+			dst := HardwareAddr{255, 255, 255, 50, 50, 50}
+			f, err := NewFrameE(src, dst, EtherTypeIPv4, []byte("HELLO"), tc.opts...)
+			if tc.wantErr != nil {
+				assert.Equal(t, tc.wantErr, err)
+				assert.Panics(t, func() { NewFrame(src, dst, EtherTypeIPv4, []byte("HELLO"), tc.opts...) })
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.wantTagged, f.Tag8021Q() != nil)
+			assert.Len(t, f.Payload(), tc.wantPayload)
+		})
+	}
+}
+
+func TestFrameFitsMTU(t *testing.T) {
+	type suite struct {
+		name    string
+		mtu     int
+		payload int
+		tagged  bool
+		want    bool
+	}
+
+	testCases := []suite{
+		{name: "positive_exact_mtu", payload: DefaultMTU, want: true},
+		{name: "negative_mtu_plus_one", payload: DefaultMTU + 1, want: false},
+		{name: "positive_tagged_exact_mtu", payload: DefaultMTU - 4, tagged: true, want: true},
+		{name: "negative_tagged_mtu_plus_one", payload: DefaultMTU - 3, tagged: true, want: false},
+		{name: "negative_tagged_untagged_mtu", payload: DefaultMTU, tagged: true, want: false},
+		{name: "positive_custom_mtu", mtu: 9000, payload: 9000, want: true},
+		{name: "negative_custom_mtu", mtu: 9000, payload: 9001, want: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			f := NewFrame(HardwareAddr{127, 127, 127, 50, 50, 50}, HardwareAddr{255, 255, 255, 50, 50, 50}, EtherTypeIPv4, make([]byte, tc.payload))
+			if tc.tagged {
+				f.SetTag8021Q(&Tag8021Q{TPID: uint16(EtherTypeVlan), TCI: Encode8021qTCI(PcpBE, 0, 1)})
+			}
+			assert.Equal(t, tc.want, f.FitsMTU(tc.mtu))
+		})
+	}
+}
+
+func TestFrameCopyTo(t *testing.T) {
+	f1 := NewFrame(HardwareAddr{127, 127, 127, 50, 50, 50}, HardwareAddr{255, 255, 255, 50, 50, 50}, EtherTypeIPv4, generatePayload(), WithVLAN(PcpVI, 0, 100))
+	f1.Marshal()
+	f2 := NewFrame(HardwareAddr{127, 127, 127, 50, 50, 51}, HardwareAddr{255, 255, 255, 50, 50, 51}, EtherTypeIPv6, []byte("HELLO"))
+	f2.Marshal()
+
+	var dst Frame
+	f1.CopyTo(&dst)
+	assert.Equal(t, *f1, dst)
+	assert.False(t, f1.Tag8021Q() == dst.Tag8021Q(), "tag must not be shared")
+	backing := &dst.Payload()[0]
+
+	f2.CopyTo(&dst)
+	assert.Equal(t, *f2, dst)
+	assert.Nil(t, dst.Tag8021Q())
+	assert.True(t, backing == &dst.Payload()[0], "payload backing array must be reused")
+
+	dst.Payload()[0] = 'J'
+	assert.Equal(t, byte('H'), f2.Payload()[0])
+}
+
+func TestFrameUnmarshalTPID(t *testing.T) {
+	for _, tpid := range VLANTPIDs {
+		t.Run(fmt.Sprintf("positive_%X", tpid), func(t *testing.T) {
+			f := NewFrame(HardwareAddr{127, 127, 127, 50, 50, 50}, HardwareAddr{255, 255, 255, 50, 50, 50}, EtherTypeIPv4, []byte("HELLO"))
+			f.SetTag8021Q(&Tag8021Q{TPID: tpid, TCI: Encode8021qTCI(PcpVI, 0, 100)})
+			b := f.Marshal()
+
+			var decoded Frame
+			assert.NoError(t, Unmarshal(b, &decoded))
+			if assert.NotNil(t, decoded.Tag8021Q()) {
+				assert.Equal(t, tpid, decoded.Tag8021Q().TPID)
+				assert.Equal(t, f.Tag8021Q().TCI, decoded.Tag8021Q().TCI)
+			}
+			assert.Equal(t, EtherTypeIPv4, decoded.EtherType())
+			assert.Equal(t, f.Payload(), decoded.Payload())
+		})
+	}
+}
+
+func TestNewFrameEPayloadBoundary(t *testing.T) {
+	type suite struct {
+		name        string
+		payload     []byte
+		wantErr     error
+		wantPayload int
+	}
+
+	testCases := []suite{
+		{name: "positive_nil", payload: nil, wantPayload: minPayloadSize},
+		{name: "positive_45", payload: bytes.Repeat([]byte{1}, 45), wantPayload: minPayloadSize},
+		{name: "positive_46", payload: bytes.Repeat([]byte{1}, 46), wantPayload: minPayloadSize},
+		{name: "positive_1500", payload: bytes.Repeat([]byte{1}, 1500), wantPayload: 1500},
+		{name: "negative_1501", payload: bytes.Repeat([]byte{1}, 1501), wantErr: ErrPayloadTooLarge},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			f, err := NewFrameE(HardwareAddr{127, 127, 127, 50, 50, 50}, HardwareAddr{255, 255, 255, 50, 50, 50}, EtherTypeIPv4, tc.payload)
+			if tc.wantErr != nil {
+				assert.Equal(t, tc.wantErr, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Len(t, f.Payload(), tc.wantPayload)
+			assert.True(t, bytes.Equal(tc.payload, f.Payload()[:len(tc.payload)]))
+			for _, b := range f.Payload()[len(tc.payload):] {
+				assert.Equal(t, byte(0), b, "padding must be zeroed")
+			}
+		})
+	}
+}
+
+func TestFramePayloadReader(t *testing.T) {
+	payload := generatePayload()
+	f := NewFrame(HardwareAddr{127, 127, 127, 50, 50, 50}, HardwareAddr{255, 255, 255, 50, 50, 50}, EtherTypeIPv4, payload)
+	b, err := ioutil.ReadAll(f.PayloadReader())
+	assert.NoError(t, err)
+	assert.Equal(t, payload, b)
+
+	// the padding is excluded
+	f = NewFrame(HardwareAddr{127, 127, 127, 50, 50, 50}, HardwareAddr{255, 255, 255, 50, 50, 50}, EtherTypeIPv4, []byte("HELLO"))
+	assert.Equal(t, minPayloadSize-5, f.PadLen())
+	b, err = ioutil.ReadAll(f.PayloadReader())
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("HELLO"), b)
+}
+
+func TestNewFrameFromToAddressOrder(t *testing.T) {
+	src := HardwareAddr{0x02, 0x00, 0x00, 0x00, 0x00, 0x01}
+	dst := HardwareAddr{0x02, 0x00, 0x00, 0x00, 0x00, 0x02}
+	for _, f := range []*Frame{NewFrameFromTo(src, dst, EtherTypeIPv4, nil), NewFrame(src, dst, EtherTypeIPv4, nil)} {
+		assert.Equal(t, src, f.Source())
+		assert.Equal(t, dst, f.Destination())
+		b := f.Marshal()
+		assert.Equal(t, dst[:], b[OffsetDst:OffsetDst+6], "destination must come first")
+		assert.Equal(t, src[:], b[OffsetSrc:OffsetSrc+6])
+	}
+}
+
+func TestExpectedFrameSize(t *testing.T) {
+	type suite struct {
+		name       string
+		payloadLen int
+		tagged     bool
+		want       int
+	}
+
+	testCases := []suite{
+		{name: "positive_empty", payloadLen: 0, want: MinFrameSize},
+		{name: "positive_45", payloadLen: 45, want: MinFrameSize},
+		{name: "positive_46", payloadLen: 46, want: MinFrameSize},
+		{name: "positive_47", payloadLen: 47, want: MinFrameSize + 1},
+		{name: "positive_45_tagged", payloadLen: 45, tagged: true, want: MinFrameSize + 4},
+		{name: "positive_47_tagged", payloadLen: 47, tagged: true, want: MinFrameSize + 5},
+		{name: "positive_1500", payloadLen: 1500, want: MaxFrameSize},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, ExpectedFrameSize(tc.payloadLen, tc.tagged))
+
+			var opts []FrameOption
+			if tc.tagged {
+				opts = append(opts, WithVLAN(PcpBE, 0, 1))
+			}
+			f := NewFrame(HardwareAddr{}, HardwareAddr{}, EtherTypeIPv4, make([]byte, tc.payloadLen), opts...)
+			assert.Len(t, f.Marshal(), tc.want)
+		})
+	}
+}
+
+func TestFrameReset(t *testing.T) {
+	f := AcquireFrame()
+	NewFrame(HardwareAddr{127, 127, 127, 50, 50, 50}, HardwareAddr{255, 255, 255, 50, 50, 50}, EtherTypeIPv4, generatePayload(), WithVLAN(PcpVI, 0, 100)).CopyTo(f)
+	f.Marshal()
+
+	f.Reset()
+	assert.Nil(t, f.Tag8021Q())
+	assert.Nil(t, f.Payload())
+	assert.Equal(t, Frame{}, *f)
+	b := f.Marshal()
+	assert.Len(t, b, minHeaderSize)
+	assert.Equal(t, make([]byte, 14), b[:14])
+	ReleaseFrame(f)
+}
+
+func TestFrameFCSUint32(t *testing.T) {
+	f := NewFrame(HardwareAddr{127, 127, 127, 50, 50, 50}, HardwareAddr{255, 255, 255, 50, 50, 50}, EtherTypeIPv4, []byte("HELLO"))
+	b := f.Marshal()
+	fcs := f.FCS()
+	assert.Equal(t, binary.BigEndian.Uint32(fcs[:]), f.FCSUint32())
+	assert.Equal(t, crc32.ChecksumIEEE(b[:len(b)-4]), f.FCSUint32())
+
+	f.SetFCSUint32(0x01020304)
+	assert.Equal(t, [4]byte{0x01, 0x02, 0x03, 0x04}, f.FCS())
+}
+
+func TestFrameUnmarshalStackedTags(t *testing.T) {
+	// outer S-Tag 0x88A8 VID 100, inner C-Tag 0x8100 VID 200
+	data := []byte{
+		127, 127, 127, 50, 50, 50, 255, 255, 255, 50, 50, 50,
+		0x88, 0xA8, 0x00, 0x64,
+		0x81, 0x00, 0x00, 0xC8,
+		0x08, 0x00,
+		72, 69, 76, 76, 79,
+	}
+	data = append(data, make([]byte, 41)...)
+	data = append(data, 123, 123, 123, 123)
+
+	var f Frame
+	assert.NoError(t, Unmarshal(data, &f))
+	if assert.NotNil(t, f.Tag8021Q()) {
+		assert.Equal(t, uint16(EtherTypeQinQ), f.Tag8021Q().TPID)
+		_, _, vlan := Decode8021qTCI(f.Tag8021Q().TCI)
+		assert.Equal(t, uint16(100), vlan)
+	}
+	if assert.Len(t, f.InnerTags(), 1) {
+		assert.Equal(t, uint16(EtherTypeVlan), f.InnerTags()[0].TPID)
+		_, _, vlan := Decode8021qTCI(f.InnerTags()[0].TCI)
+		assert.Equal(t, uint16(200), vlan)
+	}
+	assert.Equal(t, EtherTypeIPv4, f.EtherType())
+	assert.Equal(t, []byte("HELLO"), f.Payload()[:5])
+	assert.Len(t, f.Payload(), 46)
+	assert.Equal(t, len(data), f.Size())
+
+	b := f.MarshalAppend(nil)
+	assert.Equal(t, data[:len(data)-4], b[:len(b)-4])
+
+	et, ok := PeekEtherType(data)
+	assert.True(t, ok)
+	assert.Equal(t, EtherTypeIPv4, et)
+
+	// decoding an untagged frame into the same frame drops the previous tags
+	assert.NoError(t, Unmarshal(NewFrame(HardwareAddr{}, HardwareAddr{}, EtherTypeIPv6, nil).Marshal(), &f))
+	assert.Nil(t, f.Tag8021Q())
+	assert.Nil(t, f.InnerTags())
+}
+
+func TestFrameStringStackedTags(t *testing.T) {
+	f := NewFrame(HardwareAddr{127, 127, 127, 50, 50, 50}, HardwareAddr{255, 255, 255, 50, 50, 50}, EtherTypeIPv4, []byte("HELLO"), WithVLAN(PcpVI, 0, 100))
+	f.Tag8021Q().TPID = uint16(EtherTypeQinQ)
+	f.SetInnerTags([]Tag8021Q{{TPID: uint16(EtherTypeVlan), TCI: Encode8021qTCI(PcpVO, 1, 200)}})
+
+	s := f.String()
+	outer := "vlan[tpid=0x88A8 pcp=0x4(Video) dei=0x0 vlan=0x64]"
+	inner := "vlan[tpid=0x8100 pcp=0x5(Voice) dei=0x1 vlan=0xC8]"
+	assert.Contains(t, s, outer)
+	assert.Contains(t, s, inner)
+	assert.Less(t, strings.Index(s, outer), strings.Index(s, inner))
+}
+
+func TestFrameString8023(t *testing.T) {
+	src := HardwareAddr{127, 127, 127, 50, 50, 50}
+	dst := HardwareAddr{255, 255, 255, 50, 50, 50}
+	snap := EncodeSNAP(OuiRFC1042, EtherTypeARP)
+	stp := []byte{0x42, 0x42, 0x03, 0x00, 0x00}
+
+	type suite struct {
+		name       string
+		etherType  EtherType
+		payload    []byte
+		want       string
+		wantAbsent string
+	}
+
+	testCases := []suite{
+		{
+			name:       "positive_length",
+			etherType:  EtherType(len(stp)),
+			payload:    stp,
+			want:       "src=7f:7f:7f:32:32:32 len=5 size=64",
+			wantAbsent: "etherType",
+		},
+		{
+			name:       "positive_length_snap",
+			etherType:  EtherType(len(snap) + 5),
+			payload:    append(snap[:], 'H', 'E', 'L', 'L', 'O'),
+			want:       "src=7f:7f:7f:32:32:32 len=13 snap=806 size=64",
+			wantAbsent: "etherType",
+		},
+		{
+			name:       "positive_ethernet2",
+			etherType:  EtherTypeIPv4,
+			payload:    []byte("HELLO"),
+			want:       "src=7f:7f:7f:32:32:32 etherType=800 size=64",
+			wantAbsent: "len=",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			s := NewFrame(src, dst, tc.etherType, tc.payload).String()
+			assert.Contains(t, s, tc.want)
+			assert.NotContains(t, s, tc.wantAbsent)
+		})
+	}
+}
+
+func TestFrameUnmarshalTooManyTags(t *testing.T) {
+	data := []byte{127, 127, 127, 50, 50, 50, 255, 255, 255, 50, 50, 50}
+	for i := 0; i < 10; i++ {
+		data = append(data, 0x81, 0x00, 0x00, byte(i)<<4)
+	}
+	data = append(data, 0x08, 0x00)
+	data = append(data, make([]byte, 46+4)...)
+
+	type suite struct {
+		name     string
+		maxTags  int
+		wantErr  error
+		wantTags int
+	}
+
+	testCases := []suite{
+		{
+			name:    "negative_default_limit",
+			wantErr: ErrTooManyTags,
+		},
+		{
+			name:    "negative_limit_9",
+			maxTags: 9,
+			wantErr: ErrTooManyTags,
+		},
+		{
+			name:     "positive_limit_10",
+			maxTags:  10,
+			wantTags: 10,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var f Frame
+			err := UnmarshalOptions{MaxTags: tc.maxTags}.Unmarshal(data, &f)
+			assert.Equal(t, tc.wantErr, err)
+			if err == nil {
+				assert.NotNil(t, f.Tag8021Q())
+				assert.Len(t, f.InnerTags(), tc.wantTags-1)
+				assert.Equal(t, EtherTypeIPv4, f.EtherType())
+			}
+		})
+	}
+}
+
+func TestFrameUnmarshalErrorKeepsFrame(t *testing.T) {
+	tooManyTags := []byte{127, 127, 127, 50, 50, 50, 255, 255, 255, 50, 50, 50}
+	for i := 0; i < 3; i++ {
+		tooManyTags = append(tooManyTags, 0x81, 0x00, 0x00, byte(i))
+	}
+	tooManyTags = append(tooManyTags, 0x08, 0x00)
+	tooManyTags = append(tooManyTags, make([]byte, 46+4)...)
+
+	// tags run up to the end of the frame, so the last one is truncated
+	truncatedTag := []byte{127, 127, 127, 50, 50, 50, 255, 255, 255, 50, 50, 50}
+	for len(truncatedTag) < 64 {
+		truncatedTag = append(truncatedTag, 0x81, 0x00, 0x00, 0x01)
+	}
+
+	type suite struct {
+		name    string
+		opts    UnmarshalOptions
+		data    []byte
+		wantErr error
+	}
+
+	testCases := []suite{
+		{
+			name:    "negative_too_many_tags",
+			data:    tooManyTags,
+			wantErr: ErrTooManyTags,
+		},
+		{
+			name:    "negative_truncated_tag",
+			opts:    UnmarshalOptions{MaxTags: 100},
+			data:    truncatedTag,
+			wantErr: io.ErrUnexpectedEOF,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			f := NewFrame(HardwareAddr{1, 2, 3, 4, 5, 6}, HardwareAddr{6, 5, 4, 3, 2, 1}, EtherTypeARP, []byte("HELLO"))
+			f.SetTag8021Q(&Tag8021Q{TPID: uint16(EtherTypeVlan), TCI: 10})
+			want := *f
+			err := tc.opts.Unmarshal(tc.data, f)
+			assert.Equal(t, tc.wantErr, err)
+			assert.Equal(t, want, *f)
+		})
+	}
+}
+
+func TestFrameOUI(t *testing.T) {
+	f := NewFrame(HardwareAddr{0x8C, 0x8E, 0xC4, 50, 50, 50}, HardwareAddr{0x00, 0x1B, 0x21, 50, 50, 50}, EtherTypeIPv4, nil)
+	assert.Equal(t, [3]byte{0x8C, 0x8E, 0xC4}, f.SrcOUI())
+	assert.Equal(t, [3]byte{0x00, 0x1B, 0x21}, f.DstOUI())
+}
+
+func TestFrameUnmarshalTrailer(t *testing.T) {
+	f := NewFrame(HardwareAddr{127, 127, 127, 50, 50, 50}, HardwareAddr{255, 255, 255, 50, 50, 50}, EtherTypeIPv4, []byte("HELLO"))
+	data := append([]byte(nil), f.Marshal()...)
+	data = append(data, 0xDE, 0xAD, 0xBE, 0xEF)
+
+	var decoded Frame
+	assert.NoError(t, UnmarshalOptions{TrailerLen: 4}.Unmarshal(data, &decoded))
+	assert.Equal(t, []byte{0xDE, 0xAD, 0xBE, 0xEF}, decoded.Trailer())
+	assert.Equal(t, f.Payload(), decoded.Payload())
+	assert.Equal(t, f.FCS(), decoded.FCS())
+
+	// without the option the trailer is absorbed into the payload
+	assert.NoError(t, Unmarshal(data, &decoded))
+	assert.Nil(t, decoded.Trailer())
+	assert.Len(t, decoded.Payload(), len(f.Payload())+4)
+
+	assert.Equal(t, io.ErrUnexpectedEOF, UnmarshalOptions{TrailerLen: 8}.Unmarshal(data[:64], &decoded))
+}
+
+func TestFramePadLen(t *testing.T) {
+	src := HardwareAddr{127, 127, 127, 50, 50, 50}
+	dst := HardwareAddr{255, 255, 255, 50, 50, 50}
+
+	type suite struct {
+		name        string
+		frame       func() *Frame
+		wantPadLen  int
+		wantPayload []byte
+	}
+
+	testCases := []suite{
+		{
+			name:        "positive_padded",
+			frame:       func() *Frame { return NewFrame(src, dst, EtherTypeIPv4, []byte("HELLO")) },
+			wantPadLen:  minPayloadSize - 5,
+			wantPayload: []byte("HELLO"),
+		},
+		{
+			name:        "positive_min_payload",
+			frame:       func() *Frame { return NewFrameWithMinPayload(8, src, dst, EtherTypeIPv4, []byte("HELLO")) },
+			wantPadLen:  3,
+			wantPayload: []byte("HELLO"),
+		},
+		{
+			name:        "positive_unpadded",
+			frame:       func() *Frame { return NewFrame(src, dst, EtherTypeIPv4, make([]byte, minPayloadSize)) },
+			wantPadLen:  0,
+			wantPayload: make([]byte, minPayloadSize),
+		},
+		{
+			name: "positive_copy",
+			frame: func() *Frame {
+				var cp Frame
+				NewFrame(src, dst, EtherTypeIPv4, []byte("HELLO")).CopyTo(&cp)
+				return &cp
+			},
+			wantPadLen:  minPayloadSize - 5,
+			wantPayload: []byte("HELLO"),
+		},
+		{
+			name: "negative_decoded",
+			frame: func() *Frame {
+				var f Frame
+				assert.NoError(t, Unmarshal(append([]byte(nil), NewFrame(src, dst, EtherTypeIPv4, []byte("HELLO")).Marshal()...), &f))
+				return &f
+			},
+			wantPadLen: -1,
+		},
+		{
+			name:       "negative_reset",
+			frame:      func() *Frame { f := NewFrame(src, dst, EtherTypeIPv4, []byte("HELLO")); f.Reset(); return f },
+			wantPadLen: -1,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			f := tc.frame()
+			assert.Equal(t, tc.wantPadLen, f.PadLen())
+			if tc.wantPadLen >= 0 {
+				assert.Equal(t, tc.wantPayload, f.Payload()[:len(f.Payload())-f.PadLen()])
+			}
+		})
+	}
+}
+
+func TestUnmarshalMany(t *testing.T) {
+	src := HardwareAddr{127, 127, 127, 50, 50, 50}
+	dst := HardwareAddr{255, 255, 255, 50, 50, 50}
+
+	var chunks [][]byte
+	for i := 0; i < 4; i++ {
+		chunks = append(chunks, append([]byte(nil), NewFrame(src, dst, EtherTypeIPv4, []byte{byte(i)}).Marshal()...))
+	}
+	chunks[2] = chunks[2][:OffsetPayload] // corrupt: truncated
+
+	frames, errs := UnmarshalMany(chunks)
+	assert.Len(t, frames, len(chunks))
+	assert.Len(t, errs, len(chunks))
+	for i := range chunks {
+		if i == 2 {
+			assert.Equal(t, io.ErrUnexpectedEOF, errs[i])
+			assert.Nil(t, frames[i])
+			continue
+		}
+		assert.NoError(t, errs[i])
+		if assert.NotNil(t, frames[i]) {
+			assert.Equal(t, byte(i), frames[i].Payload()[0])
+		}
+	}
+
+	// the frames don't alias the chunks
+	for _, b := range chunks {
+		for j := range b {
+			b[j] = 0xFF
+		}
+	}
+	assert.Equal(t, src, frames[0].Source())
+	assert.Equal(t, byte(3), frames[3].Payload()[0])
+}
+
+func TestFrameOuterTPID(t *testing.T) {
+	src := HardwareAddr{127, 127, 127, 50, 50, 50}
+	dst := HardwareAddr{255, 255, 255, 50, 50, 50}
+
+	type suite struct {
+		name     string
+		tag      *Tag8021Q
+		wantTPID uint16
+		wantOk   bool
+	}
+
+	testCases := []suite{
+		{name: "positive_s_tag", tag: &Tag8021Q{TPID: uint16(EtherTypeQinQ), TCI: Encode8021qTCI(PcpBE, 0, 100)}, wantTPID: 0x88A8, wantOk: true},
+		{name: "positive_c_tag", tag: &Tag8021Q{TPID: uint16(EtherTypeVlan), TCI: Encode8021qTCI(PcpBE, 0, 100)}, wantTPID: 0x8100, wantOk: true},
+		{name: "negative_untagged"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			f := NewFrame(src, dst, EtherTypeIPv4, []byte("HELLO"))
+			f.SetTag8021Q(tc.tag)
+			wire := append([]byte(nil), f.Marshal()...)
+
+			var decoded Frame
+			assert.NoError(t, Unmarshal(wire, &decoded))
+			tpid, ok := decoded.OuterTPID()
+			assert.Equal(t, tc.wantTPID, tpid)
+			assert.Equal(t, tc.wantOk, ok)
+			assert.Equal(t, wire, decoded.MarshalReadOnly(), "TPID is preserved on round-trip")
+		})
+	}
+}
+
+func TestFrameUnmarshalNoFCS(t *testing.T) {
+	f := NewFrame(HardwareAddr{127, 127, 127, 50, 50, 50}, HardwareAddr{255, 255, 255, 50, 50, 50}, EtherTypeIPv4, []byte("HELLO"))
+	b := append([]byte(nil), f.Marshal()...)
+	data := b[:len(b)-4]
+
+	var decoded Frame
+	assert.NoError(t, UnmarshalOptions{NoFCS: true}.Unmarshal(data, &decoded))
+	assert.Equal(t, f.Payload(), decoded.Payload())
+	assert.Equal(t, [4]byte{}, decoded.FCS())
+	assert.Equal(t, b, decoded.Marshal())
+
+	// without the option the last 4 bytes of the payload are taken as FCS
+	assert.NoError(t, Unmarshal(data, &decoded))
+	assert.Equal(t, f.Payload()[:len(f.Payload())-4], decoded.Payload())
+
+	// runt frame without padding
+	short := append([]byte(nil), data[:OffsetPayload+5]...)
+	assert.NoError(t, UnmarshalOptions{NoFCS: true}.Unmarshal(short, &decoded))
+	assert.Equal(t, []byte("HELLO"), decoded.Payload())
+	assert.Equal(t, EtherTypeIPv4, decoded.EtherType())
+
+	assert.Equal(t, io.ErrUnexpectedEOF, UnmarshalOptions{NoFCS: true}.Unmarshal(data[:OffsetPayload-1], &decoded))
+}
+
+func TestNewBroadcastMulticastFrame(t *testing.T) {
+	src := HardwareAddr{127, 127, 127, 50, 50, 50}
+	f := NewBroadcastFrame(src, EtherTypeARP, nil)
+	assert.True(t, f.Destination().IsBroadcast())
+	assert.Equal(t, src, f.Source())
+
+	f, err := NewMulticastFrame(src, HardwareAddr{0x01, 0x00, 0x5E, 0x00, 0x00, 0x01}, EtherTypeIPv4, nil)
+	assert.NoError(t, err)
+	assert.True(t, f.Destination().IsMulticast())
+
+	_, err = NewMulticastFrame(src, HardwareAddr{0x00, 0x00, 0x5E, 0x00, 0x00, 0x01}, EtherTypeIPv4, nil)
+	assert.Equal(t, ErrNotMulticast, err)
+}
+
+func TestNewFrameWithMinPayload(t *testing.T) {
+	payload := bytes.Repeat([]byte{1}, 20)
+	f := NewFrameWithMinPayload(0, HardwareAddr{127, 127, 127, 50, 50, 50}, HardwareAddr{255, 255, 255, 50, 50, 50}, EtherTypeIPv4, payload)
+	assert.Equal(t, payload, f.Payload())
+	assert.Len(t, f.Marshal(), minHeaderSize+20)
+
+	// the spare capacity of the caller options is not written
+	opts := make([]FrameOption, 1, 2)
+	opts[0] = WithVLAN(PcpBE, 0, 100)
+	NewFrameWithMinPayload(0, HardwareAddr{127, 127, 127, 50, 50, 50}, HardwareAddr{255, 255, 255, 50, 50, 50}, EtherTypeIPv4, payload, opts...)
+	assert.Nil(t, opts[:2][1])
+}
+
+func TestFrameFixFCS(t *testing.T) {
+	data := append([]byte(nil), NewFrame(HardwareAddr{127, 127, 127, 50, 50, 50}, HardwareAddr{255, 255, 255, 50, 50, 50}, EtherTypeIPv4, []byte("HELLO")).Marshal()...)
+	assert.True(t, VerifyFCS(data))
+
+	var f Frame
+	assert.NoError(t, Unmarshal(data, &f))
+	assert.True(t, f.VerifyFCS())
+
+	f.Payload()[0] = 'J'
+	assert.False(t, f.VerifyFCS())
+	assert.False(t, VerifyFCS(data))
+
+	fixed := f.FixFCS()
+	assert.True(t, VerifyFCS(fixed))
+	assert.True(t, f.VerifyFCS())
+	assert.Equal(t, []byte("JELLO"), fixed[OffsetPayload:OffsetPayload+5])
+
+	// the stale FCS kept by FCSKeep is replaced too
+	f.SetFCSMode(FCSKeep)
+	f.Payload()[0] = 'M'
+	assert.False(t, f.VerifyFCS())
+	fixed = f.FixFCS()
+	assert.True(t, VerifyFCS(fixed))
+	assert.True(t, f.VerifyFCS())
+	assert.True(t, VerifyFCS(f.MarshalReadOnly()))
+}
+
+func TestFrameFingerprintSHA256(t *testing.T) {
+	f1 := NewFrame(HardwareAddr{127, 127, 127, 50, 50, 50}, HardwareAddr{255, 255, 255, 50, 50, 50}, EtherTypeIPv4, []byte("HELLO"))
+	f2 := NewFrame(HardwareAddr{127, 127, 127, 50, 50, 50}, HardwareAddr{255, 255, 255, 50, 50, 50}, EtherTypeIPv4, []byte("HELLO"))
+	f1.Marshal()
+	f2.SetFCS([4]byte{1, 2, 3, 4})
+	assert.NotEqual(t, f1.FCS(), f2.FCS())
+	assert.Equal(t, f1.FingerprintSHA256(), f2.FingerprintSHA256())
+
+	f3 := NewFrame(HardwareAddr{127, 127, 127, 50, 50, 50}, HardwareAddr{255, 255, 255, 50, 50, 50}, EtherTypeIPv4, []byte("JELLO"))
+	assert.NotEqual(t, f1.FingerprintSHA256(), f3.FingerprintSHA256())
+
+	tagged := NewFrame(HardwareAddr{127, 127, 127, 50, 50, 50}, HardwareAddr{255, 255, 255, 50, 50, 50}, EtherTypeIPv4, []byte("HELLO"), WithVLAN(PcpBE, 0, 10))
+	assert.NotEqual(t, f1.FingerprintSHA256(), tagged.FingerprintSHA256())
+	assert.Equal(t, f1.FingerprintSHA256Untagged(), tagged.FingerprintSHA256Untagged())
+	assert.NotNil(t, tagged.Tag8021Q())
+}
+
+func TestFramePayloadCRC32(t *testing.T) {
+	payload := []byte("123456789")
+	f := NewFrame(HardwareAddr{127, 127, 127, 50, 50, 50}, HardwareAddr{255, 255, 255, 50, 50, 50}, EtherTypeIPv4, payload, WithRawPayload())
+	assert.Equal(t, uint32(0xCBF43926), f.PayloadCRC32())
+	assert.Equal(t, uint32(0xE3069283), f.PayloadCRC32Table(crc32.MakeTable(crc32.Castagnoli)))
+
+	// headers don't affect the checksum
+	tagged := NewFrame(HardwareAddr{1, 2, 3, 4, 5, 6}, HardwareAddr{6, 5, 4, 3, 2, 1}, EtherTypeIPv4, payload, WithRawPayload(), WithVLAN(PcpBE, 0, 10))
+	assert.Equal(t, f.PayloadCRC32(), tagged.PayloadCRC32())
+}
+
+func TestFrameMarshalReadOnly(t *testing.T) {
+	f := NewFrame(HardwareAddr{127, 127, 127, 50, 50, 50}, HardwareAddr{255, 255, 255, 50, 50, 50}, EtherTypeIPv4, []byte("HELLO"), WithVLAN(PcpVI, 0, 100))
+	f.SetFCS([4]byte{1, 2, 3, 4})
+	want := append([]byte(nil), NewFrame(HardwareAddr{127, 127, 127, 50, 50, 50}, HardwareAddr{255, 255, 255, 50, 50, 50}, EtherTypeIPv4, []byte("HELLO"), WithVLAN(PcpVI, 0, 100)).Marshal()...)
+
+	var wg sync.WaitGroup
+	results := make([][]byte, 16)
+	for i := range results {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				results[i] = f.MarshalReadOnly()
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	for _, b := range results {
+		assert.Equal(t, want, b)
+		assert.True(t, VerifyFCS(b))
+	}
+	assert.Equal(t, [4]byte{1, 2, 3, 4}, f.FCS())
+}
+
+func TestFrameEtherTypeOrLength(t *testing.T) {
+	type suite struct {
+		name          string
+		etherType     EtherType
+		wantEtherType EtherType
+		wantLength    uint16
+		wantIsLength  bool
+	}
+
+	testCases := []suite{
+		{name: "positive_length", etherType: maxLength, wantLength: maxLength, wantIsLength: true},
+		{name: "positive_ethertype", etherType: EtherTypeIPv4, wantEtherType: EtherTypeIPv4},
+		{name: "positive_first_non_length", etherType: maxLength + 1, wantEtherType: maxLength + 1},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			f := NewFrame(HardwareAddr{127, 127, 127, 50, 50, 50}, HardwareAddr{255, 255, 255, 50, 50, 50}, tc.etherType, []byte("HELLO"))
+			var decoded Frame
+			assert.NoError(t, Unmarshal(f.Marshal(), &decoded))
+			et, length, isLength := decoded.EtherTypeOrLength()
+			assert.Equal(t, tc.wantEtherType, et)
+			assert.Equal(t, tc.wantLength, length)
+			assert.Equal(t, tc.wantIsLength, isLength)
+		})
+	}
+}
+
+func TestFrameEffectiveEtherType(t *testing.T) {
+	snap := EncodeSNAP(OuiRFC1042, EtherTypeARP)
+	stp := []byte{0x42, 0x42, 0x03, 0x00, 0x00}
+
+	type suite struct {
+		name          string
+		etherType     EtherType
+		payload       []byte
+		wantEtherType EtherType
+		wantErr       error
+	}
+
+	testCases := []suite{
+		{
+			name:          "positive_ethernet2",
+			etherType:     EtherTypeIPv6,
+			payload:       []byte("HELLO"),
+			wantEtherType: EtherTypeIPv6,
+		},
+		{
+			name:          "positive_8023_snap",
+			etherType:     EtherType(len(snap) + 5),
+			payload:       append(snap[:], 'H', 'E', 'L', 'L', 'O'),
+			wantEtherType: EtherTypeARP,
+		},
+		{
+			name:      "negative_8023_llc_without_snap",
+			etherType: EtherType(len(stp)),
+			payload:   stp,
+			wantErr:   ErrNoSNAP,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			f := NewFrame(HardwareAddr{127, 127, 127, 50, 50, 50}, HardwareAddr{255, 255, 255, 50, 50, 50}, tc.etherType, tc.payload)
+			var decoded Frame
+			assert.NoError(t, Unmarshal(f.Marshal(), &decoded))
+			et, err := decoded.EffectiveEtherType()
+			assert.Equal(t, tc.wantErr, err)
+			assert.Equal(t, tc.wantEtherType, et)
+		})
+	}
+}
+
+func TestFrameFCSKeep(t *testing.T) {
+	f := NewFrame(HardwareAddr{127, 127, 127, 50, 50, 50}, HardwareAddr{255, 255, 255, 50, 50, 50}, EtherTypeIPv4, []byte("HELLO"))
+	good := append([]byte(nil), f.Marshal()...)
+	bad := [4]byte{0xDE, 0xAD, 0xBE, 0xEF}
+
+	f.SetFCSMode(FCSKeep)
+	f.SetFCS(bad)
+	assert.Equal(t, FCSKeep, f.FCSMode())
+
+	type suite struct {
+		name    string
+		marshal func() []byte
+	}
+
+	testCases := []suite{
+		{
+			name:    "positive_marshal",
+			marshal: f.Marshal,
+		},
+		{
+			name:    "positive_marshal_append",
+			marshal: func() []byte { return f.MarshalAppend(nil) },
+		},
+		{
+			name:    "positive_marshal_read_only",
+			marshal: f.MarshalReadOnly,
+		},
+		{
+			name: "positive_marshal_with",
+			marshal: func() []byte {
+				b := make([]byte, f.Size())
+				n, err := f.MarshalWith(b, crc32.NewIEEE())
+				assert.NoError(t, err)
+				return b[:n]
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			b := tc.marshal()
+			assert.Equal(t, good[:len(good)-4], b[:len(b)-4])
+			assert.Equal(t, bad[:], b[len(b)-4:])
+			assert.Equal(t, bad, f.FCS())
+			assert.False(t, VerifyFCS(b))
+		})
+	}
+
+	f.SetFCSMode(FCSCompute)
+	assert.Equal(t, good, f.Marshal())
+}
+
+func TestFrameCloneWithPayload(t *testing.T) {
+	src := HardwareAddr{127, 127, 127, 50, 50, 50}
+	dst := HardwareAddr{255, 255, 255, 50, 50, 50}
+
+	type suite struct {
+		name       string
+		payload    []byte
+		fcsMode    FCSMode
+		wantPadLen int
+	}
+
+	testCases := []suite{
+		{name: "positive_repadded", payload: []byte("WORLD!"), wantPadLen: minPayloadSize - 6},
+		{name: "positive_long", payload: generatePayload(), wantPadLen: 0},
+		{name: "positive_empty", payload: nil, wantPadLen: minPayloadSize},
+		{name: "positive_fcs_keep", payload: []byte("WORLD!"), fcsMode: FCSKeep, wantPadLen: minPayloadSize - 6},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			f := NewFrame(src, dst, EtherTypeIPv4, []byte("HELLO"), WithVLAN(PcpVI, 0, 100))
+			f.SetInnerTags([]Tag8021Q{{TPID: uint16(EtherTypeVlan), TCI: Encode8021qTCI(PcpBE, 0, 200)}})
+			f.Rebuild()
+			f.SetFCSMode(tc.fcsMode)
+			orig := f.MarshalReadOnly()
+
+			clone := f.CloneWithPayload(tc.payload)
+			assert.Equal(t, f.Source(), clone.Source())
+			assert.Equal(t, f.Destination(), clone.Destination())
+			assert.Equal(t, f.EtherType(), clone.EtherType())
+			assert.Equal(t, *f.Tag8021Q(), *clone.Tag8021Q())
+			assert.Equal(t, f.InnerTags(), clone.InnerTags())
+			assert.Equal(t, tc.wantPadLen, clone.PadLen())
+			assert.Equal(t, len(tc.payload)+tc.wantPadLen, len(clone.Payload()))
+			if len(tc.payload) > 0 {
+				assert.Equal(t, tc.payload, clone.Payload()[:len(tc.payload)])
+			}
+			assert.Equal(t, FCSCompute, clone.FCSMode())
+			assert.True(t, VerifyFCS(clone.MarshalReadOnly()))
+
+			// modifications of the clone don't affect the original and p
+			want := append([]byte(nil), tc.payload...)
+			clone.Payload()[0] ^= 0xFF
+			clone.Tag8021Q().TCI = 0
+			clone.InnerTags()[0].TCI = 0
+			assert.Equal(t, orig, f.MarshalReadOnly())
+			assert.Equal(t, want, tc.payload)
+		})
+	}
+}
+
+func TestFrameRebuild(t *testing.T) {
+	src := HardwareAddr{127, 127, 127, 50, 50, 50}
+	dst := HardwareAddr{255, 255, 255, 50, 50, 50}
+
+	type suite struct {
+		name          string
+		modify        func(f *Frame)
+		wantEtherType EtherType
+		wantTag       *Tag8021Q
+	}
+
+	tag := &Tag8021Q{TPID: uint16(EtherTypeVlan), TCI: Encode8021qTCI(PcpVI, 0, 100)}
+	testCases := []suite{
+		{
+			name:          "positive_ethertype",
+			modify:        func(f *Frame) { f.SetEtherType(EtherTypeIPv6) },
+			wantEtherType: EtherTypeIPv6,
+		},
+		{
+			name: "positive_ethertype_and_tag",
+			modify: func(f *Frame) {
+				f.SetEtherType(EtherTypeARP)
+				f.SetTag8021Q(tag)
+			},
+			wantEtherType: EtherTypeARP,
+			wantTag:       tag,
+		},
+		{
+			name: "positive_fcs_keep_ignored",
+			modify: func(f *Frame) {
+				f.SetEtherType(EtherTypeIPv6)
+				f.SetFCSMode(FCSKeep)
+			},
+			wantEtherType: EtherTypeIPv6,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			orig := append([]byte(nil), NewFrame(src, dst, EtherTypeIPv4, []byte("HELLO")).Marshal()...)
+			wire := append([]byte(nil), orig...)
+
+			var f Frame
+			assert.NoError(t, Unmarshal(wire, &f))
+			tc.modify(&f)
+			b := f.Rebuild()
+			assert.Equal(t, orig, wire, "decoded buffer must not be modified")
+			assert.True(t, VerifyFCS(b))
+			fcs := f.FCS()
+			assert.Equal(t, b[len(b)-4:], fcs[:])
+
+			var decoded Frame
+			assert.NoError(t, Unmarshal(b, &decoded))
+			assert.Equal(t, tc.wantEtherType, decoded.EtherType())
+			assert.Equal(t, tc.wantTag, decoded.Tag8021Q())
+			assert.Equal(t, f.Payload(), decoded.Payload())
+			assert.True(t, decoded.VerifyFCS())
+		})
+	}
+
+	// Marshal of the unmarshaled frame reflects the changes too
+	var f Frame
+	assert.NoError(t, Unmarshal(append([]byte(nil), NewFrame(src, dst, EtherTypeIPv4, []byte("HELLO")).Marshal()...), &f))
+	f.SetEtherType(EtherTypeIPv6)
+	assert.Equal(t, f.Rebuild(), append([]byte(nil), f.Marshal()...))
+}
+
+func TestL2Frame(t *testing.T) {
+	src := HardwareAddr{127, 127, 127, 50, 50, 50}
+	dst := HardwareAddr{255, 255, 255, 50, 50, 50}
+	frames := []L2Frame{
+		NewFrame(src, dst, EtherTypeIPv4, []byte("HELLO")),
+		NewDataFrame(false, false, HardwareAddr{0x10, 0x10, 0x10, 0x10, 0x10, 0x10}, src, dst, []byte("HELLO")),
+	}
+	for _, f := range frames {
+		assert.Equal(t, src, f.Source())
+		assert.Equal(t, dst, f.Destination())
+		assert.Equal(t, []byte("HELLO"), f.Payload()[:5])
+		assert.Len(t, f.Marshal(), f.Size())
+	}
+}
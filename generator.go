@@ -0,0 +1,81 @@
+// Copyright (c) 2022 0x9ef. All rights reserved.
+// Use of this source code is governed by an MIT license
+// that can be found in the LICENSE file.
+package ethernet
+
+import "encoding/binary"
+
+// FrameTemplate describes the frames to build, fields are passed to NewFrame.
+type FrameTemplate struct {
+	Src       HardwareAddr
+	Dst       HardwareAddr
+	EtherType EtherType
+	Payload   []byte
+	Options   []FrameOption
+}
+
+// NewFrame returns a new frame built from the template, the payload is copied.
+func (t *FrameTemplate) NewFrame() (*Frame, error) {
+	payload := append([]byte(nil), t.Payload...)
+	return NewFrameE(t.Src, t.Dst, t.EtherType, payload, t.Options...)
+}
+
+// GeneratorMode selects the field which distinguishes the generated frames.
+type GeneratorMode uint8
+
+const (
+	// GenerateIncSrc increments the source address (as 48-bit number) of each frame (default).
+	GenerateIncSrc GeneratorMode = iota
+	// GenerateSeq writes big-endian 32-bit sequence number into the first 4 bytes of the payload.
+	GenerateSeq
+)
+
+// Generator produces a fixed number of frames from the template for load testing.
+// To avoid allocations, Next returns the same frame modified in place, so it's
+// valid only until the next call, use CopyTo to retain it.
+type Generator struct {
+	frame *Frame
+	mode  GeneratorMode
+	src   uint64
+	count int
+	n     int
+}
+
+// NewGenerator returns a new Generator producing count frames from the template.
+// It panics if the frame cannot be built from the template, like NewFrame.
+func NewGenerator(template *FrameTemplate, count int) *Generator {
+	f, err := template.NewFrame()
+	if err != nil {
+		panic(err)
+	}
+	var src [8]byte
+	copy(src[2:], template.Src[:])
+	return &Generator{frame: f, src: binary.BigEndian.Uint64(src[:]), count: count}
+}
+
+// SetMode sets the generator mode, it must be called before the first Next.
+func (g *Generator) SetMode(mode GeneratorMode) {
+	g.mode = mode
+	if mode == GenerateSeq && len(g.frame.payload) < 4 {
+		g.frame.payload = append(g.frame.payload, make([]byte, 4-len(g.frame.payload))...)
+	}
+}
+
+// Next returns the next frame, false when count frames are already generated.
+// The i-th frame (from 0) has the source address or the sequence number incremented by i.
+func (g *Generator) Next() (*Frame, bool) {
+	if g.n >= g.count {
+		return nil, false
+	}
+	switch g.mode {
+	case GenerateSeq:
+		binary.BigEndian.PutUint32(g.frame.payload, uint32(g.n))
+	default:
+		var src [8]byte
+		binary.BigEndian.PutUint64(src[:], g.src+uint64(g.n))
+		copy(g.frame.src[:], src[2:])
+	}
+	g.frame.invalidate()
+	g.n++
+	return g.frame, true
+}
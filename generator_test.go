@@ -0,0 +1,69 @@
+package ethernet
+
+import (
+	"encoding/binary"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGenerator(t *testing.T) {
+	template := &FrameTemplate{
+		Src:       HardwareAddr{0x02, 0x00, 0x00, 0x00, 0x00, 0xFE},
+		Dst:       HardwareAddr{255, 255, 255, 50, 50, 50},
+		EtherType: EtherTypeIPv4,
+		Payload:   []byte("HELLO"),
+	}
+
+	type suite struct {
+		name  string
+		mode  GeneratorMode
+		check func(t *testing.T, i int, f *Frame)
+	}
+
+	testCases := []suite{
+		{
+			name: "positive_inc_src",
+			mode: GenerateIncSrc,
+			check: func(t *testing.T, i int, f *Frame) {
+				want := []HardwareAddr{
+					{0x02, 0x00, 0x00, 0x00, 0x00, 0xFE},
+					{0x02, 0x00, 0x00, 0x00, 0x00, 0xFF},
+					{0x02, 0x00, 0x00, 0x00, 0x01, 0x00},
+				}
+				assert.Equal(t, want[i], f.Source())
+				assert.Equal(t, []byte("HELLO"), f.Payload()[:5])
+			},
+		},
+		{
+			name: "positive_seq",
+			mode: GenerateSeq,
+			check: func(t *testing.T, i int, f *Frame) {
+				assert.Equal(t, uint32(i), binary.BigEndian.Uint32(f.Payload()))
+				assert.Equal(t, template.Src, f.Source())
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewGenerator(template, 3)
+			g.SetMode(tc.mode)
+			var n int
+			for {
+				f, ok := g.Next()
+				if !ok {
+					break
+				}
+				tc.check(t, n, f)
+				n++
+			}
+			assert.Equal(t, 3, n)
+			assert.Equal(t, []byte("HELLO"), template.Payload)
+		})
+	}
+
+	assert.Panics(t, func() {
+		NewGenerator(&FrameTemplate{Payload: make([]byte, DefaultMTU+1)}, 1)
+	})
+}
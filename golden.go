@@ -0,0 +1,45 @@
+// Copyright (c) 2022 0x9ef. All rights reserved.
+// Use of this source code is governed by an MIT license
+// that can be found in the LICENSE file.
+package ethernet
+
+// GoldenFrames returns the curated set of frames, which cover the wire format
+// of the package: untagged, 802.1Q tagged, QinQ, ARP, IPv4 and broadcast frames.
+// The frames are built on each call, so the caller is free to modify them.
+// Their byte representation is pinned by the tests, any change of Marshal
+// output is a breaking change of the wire format.
+func GoldenFrames() []*Frame {
+	src := HardwareAddr{0x00, 0x1B, 0x21, 0x3A, 0x4B, 0x5C}
+	dst := HardwareAddr{0x00, 0x1B, 0x21, 0x6D, 0x7E, 0x8F}
+
+	qinq := NewFrame(src, dst, EtherTypeIPv4, []byte("QINQ"))
+	qinq.SetTag8021Q(&Tag8021Q{TPID: uint16(EtherTypeQinQ), TCI: Encode8021qTCI(PcpBE, 0, 100)})
+	qinq.SetInnerTags([]Tag8021Q{{TPID: uint16(EtherTypeVlan), TCI: Encode8021qTCI(PcpVI, 0, 200)}})
+
+	arp := &ARP{
+		HardwareType:       1,
+		ProtocolType:       EtherTypeIPv4,
+		HardwareLen:        6,
+		ProtocolLen:        4,
+		Operation:          ARPRequest,
+		SenderHardwareAddr: src,
+		SenderProtocolAddr: [4]byte{192, 168, 0, 1},
+		TargetProtocolAddr: [4]byte{192, 168, 0, 2},
+	}
+
+	// IPv4 header (UDP, no options) with 4 bytes of data
+	ipv4 := []byte{
+		0x45, 0x00, 0x00, 0x18, 0x00, 0x01, 0x40, 0x00,
+		0x40, 0x11, 0xB9, 0x80, 0xC0, 0xA8, 0x00, 0x01,
+		0xC0, 0xA8, 0x00, 0x02, 'P', 'I', 'N', 'G',
+	}
+
+	return []*Frame{
+		NewFrame(src, dst, EtherTypeIPv6, []byte("UNTAGGED")),
+		NewFrame(src, dst, EtherTypeIPv4, []byte("TAGGED"), WithVLAN(PcpVO, 1, 10)),
+		qinq,
+		NewBroadcastFrame(src, EtherTypeARP, arp.Marshal()),
+		NewFrame(src, dst, EtherTypeIPv4, ipv4),
+		NewBroadcastFrame(src, EtherTypeIPv4, []byte("BROADCAST")),
+	}
+}
@@ -0,0 +1,102 @@
+package ethernet
+
+import (
+	"encoding/binary"
+	"hash/crc32"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// goldenBytes is the wire format of GoldenFrames in the same order, frames are
+// encoded with the computed FCS. The bytes are assembled by the IEEE 802.3 and
+// 802.1Q field layouts, not taken from Marshal output.
+var goldenBytes = []struct {
+	name string
+	want []byte
+}{
+	{
+		name: "positive_untagged",
+		want: []byte{
+			0x00, 0x1B, 0x21, 0x6D, 0x7E, 0x8F, 0x00, 0x1B, 0x21, 0x3A, 0x4B, 0x5C, 0x86, 0xDD, 0x55, 0x4E,
+			0x54, 0x41, 0x47, 0x47, 0x45, 0x44, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+			0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+			0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x79, 0x5F, 0x53, 0xAA,
+		},
+	},
+	{
+		name: "positive_tagged",
+		want: []byte{
+			0x00, 0x1B, 0x21, 0x6D, 0x7E, 0x8F, // destination
+			0x00, 0x1B, 0x21, 0x3A, 0x4B, 0x5C, // source
+			0x81, 0x00, 0xB0, 0x0A, // C-Tag, PCP 5 (101), DEI 1, VID 10
+			0x08, 0x00, // IPv4
+			0x54, 0x41, 0x47, 0x47, 0x45, 0x44, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+			0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+			0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+			0x74, 0xA3, 0x43, 0x10, // FCS
+		},
+	},
+	{
+		name: "positive_qinq",
+		want: []byte{
+			0x00, 0x1B, 0x21, 0x6D, 0x7E, 0x8F, // destination
+			0x00, 0x1B, 0x21, 0x3A, 0x4B, 0x5C, // source
+			0x88, 0xA8, 0x00, 0x64, // S-Tag, PCP 0, DEI 0, VID 100
+			0x81, 0x00, 0x80, 0xC8, // C-Tag, PCP 4 (100), DEI 0, VID 200
+			0x08, 0x00, // IPv4
+			0x51, 0x49, 0x4E, 0x51, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+			0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+			0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+			0x58, 0x76, 0xF8, 0x2D, // FCS
+		},
+	},
+	{
+		name: "positive_arp",
+		want: []byte{
+			0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0x00, 0x1B, 0x21, 0x3A, 0x4B, 0x5C, 0x08, 0x06, 0x00, 0x01,
+			0x08, 0x00, 0x06, 0x04, 0x00, 0x01, 0x00, 0x1B, 0x21, 0x3A, 0x4B, 0x5C, 0xC0, 0xA8, 0x00, 0x01,
+			0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0xC0, 0xA8, 0x00, 0x02, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+			0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x2C, 0xF3, 0xFA, 0x96,
+		},
+	},
+	{
+		name: "positive_ipv4",
+		want: []byte{
+			0x00, 0x1B, 0x21, 0x6D, 0x7E, 0x8F, 0x00, 0x1B, 0x21, 0x3A, 0x4B, 0x5C, 0x08, 0x00, 0x45, 0x00,
+			0x00, 0x18, 0x00, 0x01, 0x40, 0x00, 0x40, 0x11, 0xB9, 0x80, 0xC0, 0xA8, 0x00, 0x01, 0xC0, 0xA8,
+			0x00, 0x02, 0x50, 0x49, 0x4E, 0x47, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+			0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x4A, 0x8A, 0x02, 0x22,
+		},
+	},
+	{
+		name: "positive_broadcast",
+		want: []byte{
+			0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0x00, 0x1B, 0x21, 0x3A, 0x4B, 0x5C, 0x08, 0x00, 0x42, 0x52,
+			0x4F, 0x41, 0x44, 0x43, 0x41, 0x53, 0x54, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+			0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+			0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x69, 0x12, 0xEF, 0x12,
+		},
+	},
+}
+
+func TestGoldenFrames(t *testing.T) {
+	frames := GoldenFrames()
+	if !assert.Len(t, frames, len(goldenBytes)) {
+		return
+	}
+
+	for i, tc := range goldenBytes {
+		f := frames[i]
+		t.Run(tc.name, func(t *testing.T) {
+			n := len(tc.want) - 4
+			assert.Equal(t, crc32.ChecksumIEEE(tc.want[:n]), binary.BigEndian.Uint32(tc.want[n:]), "FCS of the golden bytes")
+			assert.Equal(t, tc.want, f.MarshalReadOnly())
+			assert.Equal(t, tc.want, append([]byte(nil), f.Marshal()...))
+
+			var decoded Frame
+			assert.NoError(t, Unmarshal(tc.want, &decoded))
+			assert.Equal(t, tc.want, decoded.MarshalReadOnly())
+		})
+	}
+}
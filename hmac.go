@@ -0,0 +1,70 @@
+// Copyright (c) 2022 0x9ef. All rights reserved.
+// Use of this source code is governed by an MIT license
+// that can be found in the LICENSE file.
+package ethernet
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/binary"
+)
+
+// EtherTypeHMAC is the EtherType of the frames signed by SignHMAC. It's the IEEE 802
+// Local Experimental EtherType 1, the signed frame format is NOT a standard and is
+// meant only for the lab environments.
+const EtherTypeHMAC EtherType = 0x88B5
+
+// hmacHeaderSize is the original EtherType + length of the original payload.
+const hmacHeaderSize = 4
+
+// SignHMAC returns the serialized signed copy of the frame, the frame itself is not modified.
+// The signed frame keeps the addresses and VLAN tags, its EtherType is EtherTypeHMAC
+// and the payload is:
+//
+//	original EtherType (2) | original payload length (2) | original payload | HMAC-SHA256 (32)
+//
+// The HMAC covers the addresses, the tags, the original EtherType and the payload.
+// It's an application level tamper evidence (FCS catches only random errors).
+func (f *Frame) SignHMAC(key []byte) []byte {
+	payload := make([]byte, hmacHeaderSize, hmacHeaderSize+len(f.payload)+sha256.Size)
+	binary.BigEndian.PutUint16(payload[0:2], uint16(f.etherType))
+	binary.BigEndian.PutUint16(payload[2:4], uint16(len(f.payload)))
+	payload = append(payload, f.payload...)
+
+	signed := &Frame{
+		dst:       f.dst,
+		src:       f.src,
+		tag8021q:  f.tag8021q,
+		inner:     f.inner,
+		etherType: EtherTypeHMAC,
+		payload:   payload,
+	}
+	signed.payload = append(signed.payload, signed.mac(key, payload)...)
+	if pad := minPayloadSize - len(signed.payload); pad > 0 {
+		signed.payload = append(signed.payload, make([]byte, pad)...)
+	}
+	return signed.MarshalReadOnly()
+}
+
+// VerifyHMAC reports whether the frame is signed by SignHMAC with the key
+// and wasn't modified since.
+func (f *Frame) VerifyHMAC(key []byte) bool {
+	if f.etherType != EtherTypeHMAC || len(f.payload) < hmacHeaderSize {
+		return false
+	}
+	n := hmacHeaderSize + int(binary.BigEndian.Uint16(f.payload[2:4]))
+	if len(f.payload) < n+sha256.Size {
+		return false
+	}
+	return hmac.Equal(f.payload[n:n+sha256.Size], f.mac(key, f.payload[:n]))
+}
+
+// mac computes HMAC-SHA256 over the addresses, tags and the signed payload.
+func (f *Frame) mac(key, payload []byte) []byte {
+	h := hmac.New(sha256.New, key)
+	hdr := &Frame{dst: f.dst, src: f.src, tag8021q: f.tag8021q, inner: f.inner}
+	b := hdr.appendHeaderPayload(nil)
+	h.Write(b[:len(b)-2]) // without the EtherTypeHMAC
+	h.Write(payload)
+	return h.Sum(nil)
+}
@@ -0,0 +1,76 @@
+package ethernet
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFrameSignHMAC(t *testing.T) {
+	key := []byte("secret")
+	src := HardwareAddr{127, 127, 127, 50, 50, 50}
+	dst := HardwareAddr{255, 255, 255, 50, 50, 50}
+
+	type suite struct {
+		name       string
+		frame      *Frame
+		tamper     func(b []byte)
+		key        []byte
+		wantVerify bool
+	}
+
+	testCases := []suite{
+		{
+			name:       "positive_short_payload",
+			frame:      NewFrame(src, dst, EtherTypeIPv4, []byte("HELLO"), WithRawPayload()),
+			key:        key,
+			wantVerify: true,
+		},
+		{
+			name:       "positive_tagged",
+			frame:      NewFrame(src, dst, EtherTypeIPv4, generatePayload(), WithVLAN(PcpVI, 0, 100)),
+			key:        key,
+			wantVerify: true,
+		},
+		{
+			name:   "negative_tampered_payload",
+			frame:  NewFrame(src, dst, EtherTypeIPv4, []byte("HELLO"), WithRawPayload()),
+			tamper: func(b []byte) { b[OffsetPayload+hmacHeaderSize] = 'J' },
+			key:    key,
+		},
+		{
+			name:   "negative_tampered_src",
+			frame:  NewFrame(src, dst, EtherTypeIPv4, []byte("HELLO"), WithRawPayload()),
+			tamper: func(b []byte) { b[OffsetSrc] = 0 },
+			key:    key,
+		},
+		{
+			name:   "negative_tampered_ethertype",
+			frame:  NewFrame(src, dst, EtherTypeIPv4, []byte("HELLO"), WithRawPayload()),
+			tamper: func(b []byte) { b[OffsetPayload] = 0x86 },
+			key:    key,
+		},
+		{
+			name:  "negative_wrong_key",
+			frame: NewFrame(src, dst, EtherTypeIPv4, []byte("HELLO"), WithRawPayload()),
+			key:   []byte("other"),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			b := tc.frame.SignHMAC(key)
+			if tc.tamper != nil {
+				tc.tamper(b)
+			}
+			var decoded Frame
+			assert.NoError(t, Unmarshal(b, &decoded))
+			assert.Equal(t, EtherTypeHMAC, decoded.EtherType())
+			assert.Equal(t, tc.wantVerify, decoded.VerifyHMAC(tc.key))
+		})
+	}
+
+	// unsigned frame
+	f := NewFrame(src, dst, EtherTypeIPv4, []byte("HELLO"))
+	assert.False(t, f.VerifyHMAC(key))
+}
@@ -33,9 +33,14 @@ const (
 	SubtypeTrigger             = 0x2
 	SubtypeTack                = 0x3
 	SubtypeControlWrapper      = 0x7
+	SubtypeBlockAckReq         = 0x8
+	SubtypeBlockAck            = 0x9
+	SubtypePsPoll              = 0xA
 	SubtypeRts                 = 0xB
 	SubtypeCts                 = 0xC
 	SubtypeAck                 = 0xD
+	SubtypeCfEnd               = 0xE
+	SubtypeCfEndAck            = 0xF
 )
 
 func Encode80211Sc(fn uint16, sn uint16) uint16 {
@@ -73,3 +78,77 @@ func Decode80211Fc(encoded uint16) [11]uint16 {
 		(encoded >> 15) & 1, // order
 	}
 }
+
+// Cipher is the cipher suite protecting the 802.11 frame body.
+type Cipher uint8
+
+const (
+	CipherNone Cipher = iota
+	CipherWEP
+	CipherTKIP
+	CipherCCMP
+	CipherCCMP256
+	CipherGCMP
+	CipherGCMP256
+)
+
+// Overhead returns the number of bytes preceding (IV/header) and following (MIC/ICV)
+// the encrypted frame body for the cipher suite.
+func (c Cipher) Overhead() (prefix, suffix int) {
+	switch c {
+	case CipherWEP:
+		return 4, 4 // IV, ICV
+	case CipherTKIP:
+		return 8, 12 // IV/ExtIV, MIC + ICV
+	case CipherCCMP:
+		return 8, 8 // CCMP header, MIC
+	case CipherCCMP256, CipherGCMP, CipherGCMP256:
+		return 8, 16
+	default:
+		return 0, 0
+	}
+}
+
+// Capabilities is the decoded Capability Information field of beacon,
+// probe response and (re)association frames.
+type Capabilities struct {
+	ESS                bool // transmitted by AP
+	IBSS               bool // transmitted by station of ad hoc network
+	CFPollable         bool
+	CFPollRequest      bool
+	Privacy            bool // BSS requires encryption
+	ShortPreamble      bool
+	PBCC               bool
+	ChannelAgility     bool
+	SpectrumManagement bool
+	QoS                bool
+	ShortSlotTime      bool
+	APSD               bool
+	RadioMeasurement   bool
+	DSSSOFDM           bool
+	DelayedBlockAck    bool
+	ImmediateBlockAck  bool
+}
+
+// ParseCapabilities decodes the Capability Information field c, bit 0 is ESS.
+func ParseCapabilities(c uint16) Capabilities {
+	bit := func(n uint) bool { return (c>>n)&1 == 1 }
+	return Capabilities{
+		ESS:                bit(0),
+		IBSS:               bit(1),
+		CFPollable:         bit(2),
+		CFPollRequest:      bit(3),
+		Privacy:            bit(4),
+		ShortPreamble:      bit(5),
+		PBCC:               bit(6),
+		ChannelAgility:     bit(7),
+		SpectrumManagement: bit(8),
+		QoS:                bit(9),
+		ShortSlotTime:      bit(10),
+		APSD:               bit(11),
+		RadioMeasurement:   bit(12),
+		DSSSOFDM:           bit(13),
+		DelayedBlockAck:    bit(14),
+		ImmediateBlockAck:  bit(15),
+	}
+}
@@ -0,0 +1,47 @@
+package ethernet
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseCapabilities(t *testing.T) {
+	type suite struct {
+		name string
+		c    uint16
+		want Capabilities
+	}
+
+	testCases := []suite{
+		{
+			name: "positive_wpa_ap",
+			c:    0x0431,
+			want: Capabilities{ESS: true, Privacy: true, ShortPreamble: true, ShortSlotTime: true},
+		},
+		{
+			name: "positive_open_ibss",
+			c:    0x0002,
+			want: Capabilities{IBSS: true},
+		},
+		{
+			name: "positive_qos_spectrum_management",
+			c:    0x1301,
+			want: Capabilities{ESS: true, SpectrumManagement: true, QoS: true, RadioMeasurement: true},
+		},
+		{
+			name: "positive_block_ack",
+			c:    0xC000,
+			want: Capabilities{DelayedBlockAck: true, ImmediateBlockAck: true},
+		},
+		{
+			name: "positive_empty",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, ParseCapabilities(tc.c))
+		})
+	}
+}
@@ -3,17 +3,19 @@
 // that can be found in the LICENSE file.
 package ethernet
 
+// PCP is the priority code point of 802.1Q tag (802.1p user priority),
+// the values are the PCP field values of the recommended priority mapping.
 type PCP uint8
 
 const (
-	PcpBE      PCP = iota + 1 // Best Effort
-	PcpBK                     // Background
-	PcpEE                     // Excellent Effort
-	PcpCA                     // Critical Applications
-	PcpVI                     // Video, < 100 ms latency and jitter
-	PcpVO                     // Voice, < 10 ms latency and jitter
-	PcpIC                     // Internetwork Control
-	PcpNC                     // Network Control (highest)
+	PcpBE      PCP = iota // Best Effort
+	PcpBK                 // Background
+	PcpEE                 // Excellent Effort
+	PcpCA                 // Critical Applications
+	PcpVI                 // Video, < 100 ms latency and jitter
+	PcpVO                 // Voice, < 10 ms latency and jitter
+	PcpIC                 // Internetwork Control
+	PcpNC                 // Network Control (highest)
 	LowestPCP  = PcpBE
 	HighestPCP = PcpNC
 )
@@ -40,3 +42,34 @@ func (pcp PCP) String() string {
 		return "Undefined"
 	}
 }
+
+// WMM Access Categories (ACI values) of 802.11 EDCA.
+const (
+	AcBE uint8 = iota // Best Effort
+	AcBK              // Background
+	AcVI              // Video
+	AcVO              // Voice
+)
+
+// AccessCategory returns the WMM access category for the priority, according to
+// the UP-to-AC mapping of IEEE 802.11-2020 Table 10-1 by the numeric user priority:
+//
+//	UP      AC
+//	1, 2    AC_BK
+//	0, 3    AC_BE
+//	4, 5    AC_VI
+//	6, 7    AC_VO
+//
+// Undefined priorities are mapped to AC_BE.
+func (pcp PCP) AccessCategory() uint8 {
+	switch pcp {
+	case 1, 2:
+		return AcBK
+	case 4, 5:
+		return AcVI
+	case 6, 7:
+		return AcVO
+	default:
+		return AcBE
+	}
+}
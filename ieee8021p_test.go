@@ -0,0 +1,33 @@
+package ethernet
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPCPAccessCategory(t *testing.T) {
+	type suite struct {
+		name string
+		pcp  PCP
+		want uint8
+	}
+
+	testCases := []suite{
+		{name: "positive_best_effort", pcp: PcpBE, want: AcBE},
+		{name: "positive_background", pcp: PcpBK, want: AcBK},
+		{name: "positive_excellent_effort", pcp: PcpEE, want: AcBK},
+		{name: "positive_critical_applications", pcp: PcpCA, want: AcBE},
+		{name: "positive_video", pcp: PcpVI, want: AcVI},
+		{name: "positive_voice", pcp: PcpVO, want: AcVI},
+		{name: "positive_internetwork_control", pcp: PcpIC, want: AcVO},
+		{name: "positive_network_control", pcp: PcpNC, want: AcVO},
+		{name: "positive_undefined", pcp: PcpNC + 1, want: AcBE},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, tc.pcp.AccessCategory())
+		})
+	}
+}
@@ -3,21 +3,110 @@
 // that can be found in the LICENSE file.
 package ethernet
 
+import "errors"
+
+var (
+	ErrInvalidPCP  = errors.New("802.1Q PCP is out of range 0-7")
+	ErrInvalidDEI  = errors.New("802.1Q DEI is out of range 0-1")
+	ErrInvalidVLAN = errors.New("802.1Q VLAN identifier is out of range 0-4095")
+)
+
+// VLANTPIDs is a set of TPIDs recognized as VLAN tag by the decoder.
+// The TPID seen on the wire is stored in Tag8021Q.TPID.
+var VLANTPIDs = []uint16{
+	uint16(EtherTypeVlan),
+	uint16(EtherTypeQinQ),
+	uint16(EtherTypeQinQ9100),
+	uint16(EtherTypeQinQ9200),
+}
+
+// isVLANTPID reports whether tpid is one of VLANTPIDs.
+func isVLANTPID(tpid uint16) bool {
+	for _, v := range VLANTPIDs {
+		if v == tpid {
+			return true
+		}
+	}
+	return false
+}
+
 type Tag8021Q struct {
 	TPID uint16
 	TCI  uint16
 }
 
+// DEI is the drop eligible indicator, the single bit of TCI which marks the frame
+// as eligible to be dropped under congestion.
+type DEI bool
+
+// Uint16 returns the bit value of the indicator (0 or 1), which is accepted by
+// Encode8021qTCI and the other functions taking dei as uint16.
+func (d DEI) Uint16() uint16 {
+	if d {
+		return 1
+	}
+	return 0
+}
+
+// DEI returns the drop eligible indicator of the tag.
+func (t Tag8021Q) DEI() DEI { return t.TCI>>deiShift&maxDei == 1 }
+
+// SetDEI sets the drop eligible indicator of the tag.
+func (t *Tag8021Q) SetDEI(dei DEI) { t.TCI = t.TCI&^(maxDei<<deiShift) | dei.Uint16()<<deiShift }
+
+// NativeVlan is the VLAN identifier of the untagged and priority tagged frames.
+const NativeVlan = 0
+
 const maxPcp = 7     // from 0-7
 const maxDei = 1     // from 0-1
 const maxVlan = 4095 // from 0-4095
 
+// TCI is PCP (3 bits), DEI (1 bit) and VLAN identifier (12 bits) from the most significant bit.
+const (
+	pcpShift = 13
+	deiShift = 12
+)
+
 // Encode8021qTCI encodes PCP, DEI, VLAN using bitwise operations.
 func Encode8021qTCI(pcp PCP, dei uint16, vlan uint16) uint16 {
-	return (vlan << 4) | (dei << 3) | uint16(pcp)
+	return uint16(pcp)<<pcpShift | dei<<deiShift | vlan
+}
+
+// Encode8021qTCIE is like Encode8021qTCI, but returns an error if the values don't
+// fit into their TCI bit fields (see Validate8021qTCI), e.g. dei is not 0 or 1.
+func Encode8021qTCIE(pcp PCP, dei uint16, vlan uint16) (uint16, error) {
+	if err := Validate8021qTCI(pcp, dei, vlan); err != nil {
+		return 0, err
+	}
+	return Encode8021qTCI(pcp, dei, vlan), nil
+}
+
+// Validate8021qTCI checks that PCP, DEI, VLAN fit into their TCI bit fields.
+func Validate8021qTCI(pcp PCP, dei uint16, vlan uint16) error {
+	if pcp > maxPcp {
+		return ErrInvalidPCP
+	}
+	if dei > maxDei {
+		return ErrInvalidDEI
+	}
+	if vlan > maxVlan {
+		return ErrInvalidVLAN
+	}
+	return nil
 }
 
 // Decode8021qTCI decodes encoded TCI to 3 universal values PCP, DEI, VLAN
 func Decode8021qTCI(encoded uint16) (pcp PCP, dei uint16, vlan uint16) {
-	return PCP(encoded & maxPcp), (encoded >> 3) & maxDei, (encoded >> 4) & maxVlan
+	return PCP(encoded >> pcpShift & maxPcp), encoded >> deiShift & maxDei, encoded & maxVlan
+}
+
+// Encode8021qTCIBytes encodes PCP, DEI, VLAN into TCI in the big-endian wire order.
+func Encode8021qTCIBytes(pcp PCP, dei uint16, vlan uint16) [2]byte {
+	tci := Encode8021qTCI(pcp, dei, vlan)
+	return [2]byte{byte(tci >> 8), byte(tci)}
+}
+
+// Decode8021qTCIBytes decodes TCI in the big-endian wire order to PCP, DEI, VLAN.
+func Decode8021qTCIBytes(b [2]byte) (pcp PCP, dei uint16, vlan uint16) {
+	return Decode8021qTCI(uint16(b[0])<<8 | uint16(b[1]))
 }
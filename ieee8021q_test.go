@@ -0,0 +1,86 @@
+package ethernet
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEncode8021qTCIBytes(t *testing.T) {
+	// PCP 5, DEI 1, VID 100 as specified by IEEE 802.1Q
+	b := Encode8021qTCIBytes(PcpVO, 1, 100)
+	assert.Equal(t, [2]byte{0xB0, 0x64}, b)
+
+	pcp, dei, vlan := Decode8021qTCIBytes(b)
+	assert.Equal(t, PcpVO, pcp)
+	assert.Equal(t, uint16(1), dei)
+	assert.Equal(t, uint16(100), vlan)
+
+	// bytes of the tag as they appear on the wire after Marshal
+	f := NewFrame(HardwareAddr{}, HardwareAddr{}, EtherTypeIPv4, nil, WithVLAN(PcpVO, 1, 100))
+	wire := f.Marshal()
+	assert.Equal(t, b, [2]byte{wire[OffsetTCI], wire[OffsetTCI+1]})
+}
+
+func TestDEI(t *testing.T) {
+	assert.Equal(t, uint16(1), DEI(true).Uint16())
+	assert.Equal(t, uint16(0), DEI(false).Uint16())
+
+	tci := Encode8021qTCI(PcpVI, DEI(true).Uint16(), 100)
+	_, dei, _ := Decode8021qTCI(tci)
+	assert.Equal(t, uint16(1), dei)
+	assert.Equal(t, uint16(1<<12), tci&(1<<12))
+
+	tag := Tag8021Q{TPID: uint16(EtherTypeVlan), TCI: tci}
+	assert.Equal(t, DEI(true), tag.DEI())
+	tag.SetDEI(false)
+	assert.Equal(t, DEI(false), tag.DEI())
+	assert.Equal(t, Encode8021qTCI(PcpVI, 0, 100), tag.TCI, "PCP and VLAN are preserved")
+	tag.SetDEI(true)
+	assert.Equal(t, tci, tag.TCI)
+}
+
+func TestEncode8021qTCIE(t *testing.T) {
+	type suite struct {
+		name    string
+		pcp     PCP
+		dei     uint16
+		vlan    uint16
+		wantErr error
+	}
+
+	testCases := []suite{
+		{name: "positive_dei_0", pcp: PcpVI, dei: 0, vlan: 100},
+		{name: "positive_dei_1", pcp: PcpVI, dei: 1, vlan: 100},
+		{name: "negative_dei_2", pcp: PcpVI, dei: 2, vlan: 100, wantErr: ErrInvalidDEI},
+		{name: "negative_vlan", pcp: PcpVI, dei: 0, vlan: maxVlan + 1, wantErr: ErrInvalidVLAN},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tci, err := Encode8021qTCIE(tc.pcp, tc.dei, tc.vlan)
+			assert.Equal(t, tc.wantErr, err)
+			if tc.wantErr == nil {
+				assert.Equal(t, Encode8021qTCI(tc.pcp, tc.dei, tc.vlan), tci)
+			}
+		})
+	}
+}
+
+func TestWithVLANPCPRoundTrip(t *testing.T) {
+	src := HardwareAddr{127, 127, 127, 50, 50, 50}
+	dst := HardwareAddr{255, 255, 255, 50, 50, 50}
+
+	for pcp := LowestPCP; pcp <= HighestPCP; pcp++ {
+		t.Run(pcp.String(), func(t *testing.T) {
+			f, err := NewFrameE(src, dst, EtherTypeIPv4, nil, WithVLAN(pcp, 1, 100))
+			if !assert.NoError(t, err) {
+				return
+			}
+			gotPcp, dei, vlan := Decode8021qTCI(f.Tag8021Q().TCI)
+			assert.Equal(t, pcp, gotPcp)
+			assert.Equal(t, uint16(1), dei)
+			assert.Equal(t, uint16(100), vlan)
+		})
+	}
+}
@@ -0,0 +1,77 @@
+// Copyright (c) 2022 0x9ef. All rights reserved.
+// Use of this source code is governed by an MIT license
+// that can be found in the LICENSE file.
+package ethernet
+
+import (
+	"errors"
+	"io"
+)
+
+// ErrElementTooLong is returned when the information element data exceeds 255 bytes.
+var ErrElementTooLong = errors.New("information element data exceeds 255 bytes")
+
+// Information element identifiers.
+const (
+	ElementSSID           uint8 = 0
+	ElementSupportedRates uint8 = 1
+	ElementDSParameterSet uint8 = 3
+	ElementTIM            uint8 = 5
+	ElementHTOperation    uint8 = 61
+)
+
+// maxElementData is the maximum length of the element data, the length is a single octet.
+const maxElementData = 255
+
+// InfoElement is an information element carried in the body of the 802.11
+// management frames (beacons, probe requests, etc), encoded as ID/Length/Data.
+type InfoElement struct {
+	ID   uint8
+	Data []byte
+}
+
+// Marshal serializes the element into the byte representation.
+// Returns ErrElementTooLong if the data exceeds 255 bytes.
+func (e InfoElement) Marshal() ([]byte, error) {
+	return e.appendTo(make([]byte, 0, 2+len(e.Data)))
+}
+
+func (e InfoElement) appendTo(b []byte) ([]byte, error) {
+	if len(e.Data) > maxElementData {
+		return nil, ErrElementTooLong
+	}
+	b = append(b, e.ID, byte(len(e.Data)))
+	return append(b, e.Data...), nil
+}
+
+// EncodeInfoElements serializes the elements into a TLV stream in the given order.
+// Returns ErrElementTooLong if data of any element exceeds 255 bytes.
+func EncodeInfoElements(elems []InfoElement) ([]byte, error) {
+	var n int
+	for _, e := range elems {
+		n += 2 + len(e.Data)
+	}
+	b := make([]byte, 0, n)
+	for _, e := range elems {
+		var err error
+		if b, err = e.appendTo(b); err != nil {
+			return nil, err
+		}
+	}
+	return b, nil
+}
+
+// ParseInfoElements decodes a TLV stream of information elements.
+// The elements data alias b.
+func ParseInfoElements(b []byte) ([]InfoElement, error) {
+	var elems []InfoElement
+	for len(b) > 0 {
+		if len(b) < 2 || len(b) < 2+int(b[1]) {
+			return nil, io.ErrUnexpectedEOF
+		}
+		n := 2 + int(b[1])
+		elems = append(elems, InfoElement{ID: b[0], Data: b[2:n]})
+		b = b[n:]
+	}
+	return elems, nil
+}
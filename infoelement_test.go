@@ -0,0 +1,40 @@
+package ethernet
+
+import (
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEncodeInfoElements(t *testing.T) {
+	elems := []InfoElement{
+		{ID: ElementSSID, Data: []byte("test-ap")},
+		{ID: ElementSupportedRates, Data: []byte{0x82, 0x84, 0x8B, 0x96}},
+	}
+	b, err := EncodeInfoElements(elems)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{
+		0x00, 0x07, 't', 'e', 's', 't', '-', 'a', 'p',
+		0x01, 0x04, 0x82, 0x84, 0x8B, 0x96,
+	}, b)
+
+	ssid, err := elems[0].Marshal()
+	assert.NoError(t, err)
+	assert.Equal(t, b[:9], ssid)
+
+	decoded, err := ParseInfoElements(b)
+	assert.NoError(t, err)
+	assert.Equal(t, elems, decoded)
+
+	_, err = ParseInfoElements(b[:len(b)-1])
+	assert.Equal(t, io.ErrUnexpectedEOF, err)
+}
+
+func TestEncodeInfoElementsTooLong(t *testing.T) {
+	_, err := EncodeInfoElements([]InfoElement{{ID: ElementSSID, Data: make([]byte, 256)}})
+	assert.Equal(t, ErrElementTooLong, err)
+
+	_, err = InfoElement{ID: ElementSSID, Data: make([]byte, 255)}.Marshal()
+	assert.NoError(t, err)
+}
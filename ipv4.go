@@ -0,0 +1,88 @@
+// Copyright (c) 2022 0x9ef. All rights reserved.
+// Use of this source code is governed by an MIT license
+// that can be found in the LICENSE file.
+package ethernet
+
+import (
+	"encoding/binary"
+	"errors"
+	"io"
+)
+
+var (
+	// ErrBadIPVersion is returned when the version nibble doesn't match the parsed IP header.
+	ErrBadIPVersion = errors.New("unexpected IP version")
+	// ErrBadIPHeader is returned when the IP header length fields are inconsistent.
+	ErrBadIPHeader = errors.New("malformed IP header")
+	// ErrEtherTypeMismatch is returned when the payload is decoded as a protocol,
+	// which doesn't match the frame EtherType.
+	ErrEtherTypeMismatch = errors.New("frame EtherType doesn't match the requested protocol")
+)
+
+// minIPv4HeaderSize is the size of IPv4 header without options.
+const minIPv4HeaderSize = 20
+
+// IPv4Header is the header of Internet Protocol version 4 packet.
+//
+// https://datatracker.ietf.org/doc/html/rfc791
+type IPv4Header struct {
+	Version        uint8
+	IHL            uint8 // header length in 32-bit words
+	TOS            uint8
+	TotalLength    uint16
+	ID             uint16
+	Flags          uint8
+	FragmentOffset uint16
+	TTL            uint8
+	Protocol       uint8
+	Checksum       uint16
+	Src            [4]byte
+	Dst            [4]byte
+	Options        []byte
+}
+
+// ParseIPv4 decodes IPv4 header from b and returns the header with the remaining
+// bytes of the packet. The remaining bytes are limited by the TotalLength,
+// so the Ethernet padding is not included.
+func ParseIPv4(b []byte) (*IPv4Header, []byte, error) {
+	if len(b) < minIPv4HeaderSize {
+		return nil, nil, io.ErrUnexpectedEOF
+	}
+	h := &IPv4Header{
+		Version:        b[0] >> 4,
+		IHL:            b[0] & 0x0F,
+		TOS:            b[1],
+		TotalLength:    binary.BigEndian.Uint16(b[2:4]),
+		ID:             binary.BigEndian.Uint16(b[4:6]),
+		Flags:          b[6] >> 5,
+		FragmentOffset: binary.BigEndian.Uint16(b[6:8]) & 0x1FFF,
+		TTL:            b[8],
+		Protocol:       b[9],
+		Checksum:       binary.BigEndian.Uint16(b[10:12]),
+	}
+	if h.Version != 4 {
+		return nil, nil, ErrBadIPVersion
+	}
+	hlen := int(h.IHL) * 4
+	if hlen < minIPv4HeaderSize || int(h.TotalLength) < hlen {
+		return nil, nil, ErrBadIPHeader
+	}
+	if len(b) < int(h.TotalLength) {
+		return nil, nil, io.ErrUnexpectedEOF
+	}
+	copy(h.Src[:], b[12:16])
+	copy(h.Dst[:], b[16:20])
+	if hlen > minIPv4HeaderSize {
+		h.Options = b[minIPv4HeaderSize:hlen]
+	}
+	return h, b[hlen:h.TotalLength], nil
+}
+
+// IPv4 decodes the frame payload as IPv4 packet, returns the header and the remaining bytes.
+// Returns ErrEtherTypeMismatch if the frame EtherType is not EtherTypeIPv4.
+func (f *Frame) IPv4() (*IPv4Header, []byte, error) {
+	if f.etherType != EtherTypeIPv4 {
+		return nil, nil, ErrEtherTypeMismatch
+	}
+	return ParseIPv4(f.payload)
+}
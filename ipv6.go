@@ -0,0 +1,74 @@
+// Copyright (c) 2022 0x9ef. All rights reserved.
+// Use of this source code is governed by an MIT license
+// that can be found in the LICENSE file.
+package ethernet
+
+import (
+	"encoding/binary"
+	"io"
+)
+
+// ipv6HeaderSize is the size of IPv6 fixed header.
+const ipv6HeaderSize = 40
+
+// IPv6Header is the fixed header of Internet Protocol version 6 packet.
+//
+// https://datatracker.ietf.org/doc/html/rfc8200
+type IPv6Header struct {
+	Version       uint8
+	TrafficClass  uint8
+	FlowLabel     uint32
+	PayloadLength uint16
+	NextHeader    uint8
+	HopLimit      uint8
+	Src           [16]byte
+	Dst           [16]byte
+}
+
+// ParseIPv6 decodes IPv6 fixed header from b and returns the header with the remaining
+// bytes of the packet. The remaining bytes are limited by the PayloadLength,
+// so the Ethernet padding is not included.
+func ParseIPv6(b []byte) (*IPv6Header, []byte, error) {
+	if len(b) < ipv6HeaderSize {
+		return nil, nil, io.ErrUnexpectedEOF
+	}
+	vtf := binary.BigEndian.Uint32(b[0:4])
+	h := &IPv6Header{
+		Version:       uint8(vtf >> 28),
+		TrafficClass:  uint8(vtf >> 20),
+		FlowLabel:     vtf & 0xFFFFF,
+		PayloadLength: binary.BigEndian.Uint16(b[4:6]),
+		NextHeader:    b[6],
+		HopLimit:      b[7],
+	}
+	if h.Version != 6 {
+		return nil, nil, ErrBadIPVersion
+	}
+	if len(b) < ipv6HeaderSize+int(h.PayloadLength) {
+		return nil, nil, io.ErrUnexpectedEOF
+	}
+	copy(h.Src[:], b[8:24])
+	copy(h.Dst[:], b[24:40])
+	return h, b[ipv6HeaderSize : ipv6HeaderSize+int(h.PayloadLength)], nil
+}
+
+// Marshal encodes the fixed header, the Version is always encoded as 6.
+func (h *IPv6Header) Marshal() []byte {
+	b := make([]byte, ipv6HeaderSize)
+	binary.BigEndian.PutUint32(b[0:4], 6<<28|uint32(h.TrafficClass)<<20|h.FlowLabel&0xFFFFF)
+	binary.BigEndian.PutUint16(b[4:6], h.PayloadLength)
+	b[6] = h.NextHeader
+	b[7] = h.HopLimit
+	copy(b[8:24], h.Src[:])
+	copy(b[24:40], h.Dst[:])
+	return b
+}
+
+// IPv6 decodes the frame payload as IPv6 packet, returns the header and the remaining bytes.
+// Returns ErrEtherTypeMismatch if the frame EtherType is not EtherTypeIPv6.
+func (f *Frame) IPv6() (*IPv6Header, []byte, error) {
+	if f.etherType != EtherTypeIPv6 {
+		return nil, nil, ErrEtherTypeMismatch
+	}
+	return ParseIPv6(f.payload)
+}
@@ -0,0 +1,20 @@
+// Copyright (c) 2022 0x9ef. All rights reserved.
+// Use of this source code is governed by an MIT license
+// that can be found in the LICENSE file.
+package ethernet
+
+// L2Frame is the set of methods shared by Ethernet (Frame) and 802.11 (Frame80211)
+// frames, so the generic code can process frames of both link layer technologies.
+type L2Frame interface {
+	Source() HardwareAddr
+	Destination() HardwareAddr
+	Payload() []byte
+	FCS() [4]byte
+	Size() int
+	Marshal() []byte
+}
+
+var (
+	_ L2Frame = (*Frame)(nil)
+	_ L2Frame = (*Frame80211)(nil)
+)
@@ -0,0 +1,69 @@
+// Copyright (c) 2022 0x9ef. All rights reserved.
+// Use of this source code is governed by an MIT license
+// that can be found in the LICENSE file.
+package ethernet
+
+import (
+	"encoding/binary"
+	"errors"
+	"io"
+)
+
+// ErrNoSNAP is returned when the payload doesn't start with the LLC/SNAP header.
+var ErrNoSNAP = errors.New("payload doesn't start with LLC/SNAP header")
+
+// snapHeaderSize is LLC (DSAP, SSAP, Control) + SNAP (OUI, PID)
+const snapHeaderSize = 8
+
+var (
+	// OuiRFC1042 is the SNAP OUI used to encapsulate Ethernet II frames (RFC 1042).
+	OuiRFC1042 = [3]byte{0x00, 0x00, 0x00}
+	// Oui8021H is the SNAP OUI of the 802.1H bridge tunnel encapsulation.
+	Oui8021H = [3]byte{0x00, 0x00, 0xF8}
+)
+
+// EncodeSNAP returns LLC/SNAP header (DSAP=0xAA, SSAP=0xAA, Control=0x03)
+// with the given OUI and protocol identifier.
+func EncodeSNAP(oui [3]byte, pid EtherType) [snapHeaderSize]byte {
+	return [snapHeaderSize]byte{
+		0xAA, 0xAA, 0x03,
+		oui[0], oui[1], oui[2],
+		byte(pid >> 8), byte(pid),
+	}
+}
+
+// DecodeSNAP decodes LLC/SNAP header from b, returns OUI, protocol identifier and the remaining bytes.
+func DecodeSNAP(b []byte) (oui [3]byte, pid EtherType, rest []byte, err error) {
+	if len(b) < snapHeaderSize {
+		return oui, 0, nil, io.ErrUnexpectedEOF
+	}
+	if b[0] != 0xAA || b[1] != 0xAA || b[2] != 0x03 {
+		return oui, 0, nil, ErrNoSNAP
+	}
+	copy(oui[:], b[3:6])
+	return oui, EtherType(binary.BigEndian.Uint16(b[6:8])), b[snapHeaderSize:], nil
+}
+
+// EtherTypeOrLength returns the interpretation of the EtherType/Length field:
+// the payload length of IEEE 802.3 frame (isLength is true), or the EtherType
+// of Ethernet II frame. The other return value is zero.
+func (f *Frame) EtherTypeOrLength() (et EtherType, length uint16, isLength bool) {
+	if f.etherType.IsLength() {
+		return 0, uint16(f.etherType), true
+	}
+	return f.etherType, 0, false
+}
+
+// EffectiveEtherType returns the protocol encapsulated in the frame: the EtherType of
+// Ethernet II frame, or the SNAP protocol identifier of IEEE 802.3 (LLC/SNAP) frame.
+// Returns ErrNoSNAP if 802.3 frame payload is LLC without SNAP header.
+func (f *Frame) EffectiveEtherType() (EtherType, error) {
+	if !f.etherType.IsLength() {
+		return f.etherType, nil
+	}
+	_, pid, _, err := DecodeSNAP(f.payload)
+	if err != nil {
+		return 0, err
+	}
+	return pid, nil
+}
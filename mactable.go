@@ -0,0 +1,70 @@
+// Copyright (c) 2022 0x9ef. All rights reserved.
+// Use of this source code is governed by an MIT license
+// that can be found in the LICENSE file.
+package ethernet
+
+import (
+	"sync"
+	"time"
+)
+
+type macEntry struct {
+	port int
+	seen time.Time
+}
+
+// MACTable is the forwarding database of a software bridge, it maps the learned
+// source addresses to the ports they were seen on. The zero value is an empty table,
+// it's safe for concurrent use.
+type MACTable struct {
+	mu      sync.RWMutex
+	entries map[HardwareAddr]macEntry
+	now     func() time.Time // time source, time.Now if nil
+}
+
+func (t *MACTable) clock() time.Time {
+	if t.now != nil {
+		return t.now()
+	}
+	return time.Now()
+}
+
+// Learn records that src is reachable via the port, typically called with
+// the Source() of every received frame. If the address was learned on another
+// port (the station moved), the entry is moved to the new port.
+func (t *MACTable) Learn(src HardwareAddr, port int) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.entries == nil {
+		t.entries = make(map[HardwareAddr]macEntry)
+	}
+	t.entries[src] = macEntry{port: port, seen: t.clock()}
+}
+
+// Lookup returns the port dst was learned on. If the address is unknown,
+// the frame is expected to be flooded.
+func (t *MACTable) Lookup(dst HardwareAddr) (port int, known bool) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	e, ok := t.entries[dst]
+	return e.port, ok
+}
+
+// Age removes the entries which were not learned for longer than d.
+func (t *MACTable) Age(d time.Duration) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	deadline := t.clock().Add(-d)
+	for addr, e := range t.entries {
+		if e.seen.Before(deadline) {
+			delete(t.entries, addr)
+		}
+	}
+}
+
+// Len returns the number of learned addresses.
+func (t *MACTable) Len() int {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return len(t.entries)
+}
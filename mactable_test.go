@@ -0,0 +1,74 @@
+package ethernet
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMACTable(t *testing.T) {
+	a := HardwareAddr{127, 127, 127, 50, 50, 50}
+	b := HardwareAddr{255, 255, 255, 50, 50, 50}
+
+	now := time.Unix(1000, 0)
+	tab := &MACTable{now: func() time.Time { return now }}
+
+	// lookup in the empty table
+	_, known := tab.Lookup(a)
+	assert.False(t, known)
+
+	// learn
+	tab.Learn(a, 1)
+	port, known := tab.Lookup(a)
+	assert.True(t, known)
+	assert.Equal(t, 1, port)
+	_, known = tab.Lookup(b)
+	assert.False(t, known)
+
+	// move (MAC flap)
+	tab.Learn(a, 2)
+	port, known = tab.Lookup(a)
+	assert.True(t, known)
+	assert.Equal(t, 2, port)
+	assert.Equal(t, 1, tab.Len())
+
+	// aging
+	now = now.Add(10 * time.Second)
+	tab.Learn(b, 3)
+	now = now.Add(5 * time.Second)
+	tab.Age(10 * time.Second)
+	_, known = tab.Lookup(a)
+	assert.False(t, known)
+	port, known = tab.Lookup(b)
+	assert.True(t, known)
+	assert.Equal(t, 3, port)
+
+	// learning refreshes the entry
+	now = now.Add(4 * time.Second)
+	tab.Learn(b, 3)
+	now = now.Add(9 * time.Second)
+	tab.Age(10 * time.Second)
+	_, known = tab.Lookup(b)
+	assert.True(t, known)
+}
+
+func TestMACTableConcurrent(t *testing.T) {
+	var tab MACTable
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			addr := HardwareAddr{0x02, 0, 0, 0, 0, byte(i)}
+			for j := 0; j < 100; j++ {
+				tab.Learn(addr, i)
+				tab.Lookup(addr)
+				tab.Age(time.Hour)
+			}
+		}(i)
+	}
+	wg.Wait()
+	assert.Equal(t, 8, tab.Len())
+}
@@ -0,0 +1,279 @@
+// Copyright (c) 2022 0x9ef. All rights reserved.
+// Use of this source code is governed by an MIT license
+// that can be found in the LICENSE file.
+package ethernet
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"io"
+	"time"
+)
+
+var (
+	// ErrBadPcapMagic is returned when the stream doesn't start with pcap magic number.
+	ErrBadPcapMagic = errors.New("bad pcap magic number")
+	// ErrPcapRecordTooLong is returned when the captured length of the record exceeds the snaplen.
+	ErrPcapRecordTooLong = errors.New("pcap record is longer than the snaplen")
+)
+
+const (
+	pcapMagicMicro = 0xA1B2C3D4
+	pcapMagicNano  = 0xA1B23C4D
+
+	pcapHeaderSize       = 24
+	pcapRecordHeaderSize = 16
+	pcapDefaultSnaplen   = 65535
+	pcapMaxSnaplen       = 262144 // MAXIMUM_SNAPLEN of libpcap
+)
+
+// Link types of the pcap file.
+//
+// https://www.tcpdump.org/linktypes.html
+const (
+	LinkTypeEthernet          uint32 = 1
+	LinkTypeIEEE80211         uint32 = 105 // 802.11 frames without radio information
+	LinkTypeIEEE80211RadioTap uint32 = 127 // 802.11 frames prepended by RadioTap header
+)
+
+// LinkTypeFor returns the link type of the pcap file to store the frame,
+// or 0 if the frame type is unknown. 802.11 frames stored with RadioTap
+// headers use LinkTypeIEEE80211RadioTap instead.
+func LinkTypeFor(f L2Frame) uint32 {
+	switch f.(type) {
+	case *Frame:
+		return LinkTypeEthernet
+	case *Frame80211:
+		return LinkTypeIEEE80211
+	default:
+		return 0
+	}
+}
+
+// CaptureInfo is the metadata of the captured packet stored in the pcap record header.
+type CaptureInfo struct {
+	Timestamp     time.Time
+	CaptureLength int // number of bytes stored in the file
+	Length        int // original length of the packet on the wire
+}
+
+// PcapReader reads packets from the libpcap file format.
+// Both microsecond and nanosecond resolution files in either byte order are supported.
+//
+// https://wiki.wireshark.org/Development/LibpcapFileFormat
+type PcapReader struct {
+	r        io.Reader
+	order    binary.ByteOrder
+	nano     bool
+	linkType uint32
+	snaplen  uint32
+	limit    int // snaplen set by SetSnaplen
+	hdr      [pcapRecordHeaderSize]byte
+}
+
+// NewPcapReader reads the pcap global header from r and returns a new PcapReader.
+func NewPcapReader(r io.Reader) (*PcapReader, error) {
+	var hdr [pcapHeaderSize]byte
+	if _, err := io.ReadFull(r, hdr[:]); err != nil {
+		return nil, err
+	}
+
+	pr := &PcapReader{r: r}
+	switch {
+	case binary.LittleEndian.Uint32(hdr[0:4]) == pcapMagicMicro:
+		pr.order = binary.LittleEndian
+	case binary.LittleEndian.Uint32(hdr[0:4]) == pcapMagicNano:
+		pr.order, pr.nano = binary.LittleEndian, true
+	case binary.BigEndian.Uint32(hdr[0:4]) == pcapMagicMicro:
+		pr.order = binary.BigEndian
+	case binary.BigEndian.Uint32(hdr[0:4]) == pcapMagicNano:
+		pr.order, pr.nano = binary.BigEndian, true
+	default:
+		return nil, ErrBadPcapMagic
+	}
+	pr.snaplen = pr.order.Uint32(hdr[16:20])
+	pr.linkType = pr.order.Uint32(hdr[20:24])
+	return pr, nil
+}
+
+// LinkType returns the link type of the packets in the file.
+func (pr *PcapReader) LinkType() uint32 { return pr.linkType }
+
+// Snaplen returns the maximum length of the captured packets.
+func (pr *PcapReader) Snaplen() uint32 { return pr.snaplen }
+
+// SetSnaplen sets the maximum number of bytes of the frame to decode by ReadFrame,
+// longer frames are truncated. The default of 0 means no limit, besides the
+// snaplen of the capture.
+func (pr *PcapReader) SetSnaplen(snaplen int) { pr.limit = snaplen }
+
+// ReadPacket reads the next packet, the returned buffer is owned by the caller.
+// Returns io.EOF when there are no more packets, and ErrPcapRecordTooLong if the
+// captured length exceeds the snaplen of the file (262144 if the snaplen is 0 or larger),
+// so a corrupted record doesn't cause a huge allocation.
+func (pr *PcapReader) ReadPacket() ([]byte, CaptureInfo, error) {
+	if _, err := io.ReadFull(pr.r, pr.hdr[:]); err != nil {
+		return nil, CaptureInfo{}, err
+	}
+	sec := int64(pr.order.Uint32(pr.hdr[0:4]))
+	frac := int64(pr.order.Uint32(pr.hdr[4:8]))
+	if !pr.nano {
+		frac *= int64(time.Microsecond)
+	}
+	caplen := pr.order.Uint32(pr.hdr[8:12])
+	if caplen > pr.maxCaptureLength() {
+		return nil, CaptureInfo{}, ErrPcapRecordTooLong
+	}
+	ci := CaptureInfo{
+		Timestamp:     time.Unix(sec, frac).UTC(),
+		CaptureLength: int(caplen),
+		Length:        int(pr.order.Uint32(pr.hdr[12:16])),
+	}
+
+	b := make([]byte, ci.CaptureLength)
+	if _, err := io.ReadFull(pr.r, b); err != nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return nil, CaptureInfo{}, err
+	}
+	return b, ci, nil
+}
+
+// maxCaptureLength returns the maximum captured length of the record.
+func (pr *PcapReader) maxCaptureLength() uint32 {
+	if pr.snaplen == 0 || pr.snaplen > pcapMaxSnaplen {
+		return pcapMaxSnaplen
+	}
+	return pr.snaplen
+}
+
+// ReadFrame reads the next packet and decodes it as Ethernet frame. If the record
+// is truncated (the captured length is less than the original length) or longer than
+// the snaplen, the frame is decoded without FCS and reported by Frame.IsTruncated.
+func (pr *PcapReader) ReadFrame() (*Frame, CaptureInfo, error) {
+	b, ci, err := pr.ReadPacket()
+	if err != nil {
+		return nil, ci, err
+	}
+	origLen := ci.Length
+	if origLen < len(b) {
+		origLen = len(b)
+	}
+	if pr.limit > 0 && len(b) > pr.limit {
+		b = b[:pr.limit]
+	}
+	f := new(Frame)
+	if len(b) < origLen {
+		err = unmarshalTruncated(b, origLen, f)
+	} else {
+		err = Unmarshal(b, f)
+	}
+	if err != nil {
+		return nil, ci, err
+	}
+	return f, ci, nil
+}
+
+// PcapWriter writes packets in the libpcap file format with microsecond resolution.
+type PcapWriter struct {
+	w   io.Writer
+	hdr [pcapRecordHeaderSize]byte
+}
+
+// NewPcapWriter writes the pcap global header with the given link type to w
+// and returns a new PcapWriter.
+func NewPcapWriter(w io.Writer, linkType uint32) (*PcapWriter, error) {
+	var hdr [pcapHeaderSize]byte
+	binary.LittleEndian.PutUint32(hdr[0:4], pcapMagicMicro)
+	binary.LittleEndian.PutUint16(hdr[4:6], 2) // version major
+	binary.LittleEndian.PutUint16(hdr[6:8], 4) // version minor
+	binary.LittleEndian.PutUint32(hdr[16:20], pcapDefaultSnaplen)
+	binary.LittleEndian.PutUint32(hdr[20:24], linkType)
+	if _, err := w.Write(hdr[:]); err != nil {
+		return nil, err
+	}
+	return &PcapWriter{w: w}, nil
+}
+
+// WritePacket writes a packet record, if ci.CaptureLength or ci.Length is zero,
+// the length of data is used.
+func (pw *PcapWriter) WritePacket(ci CaptureInfo, data []byte) error {
+	capLen, origLen := ci.CaptureLength, ci.Length
+	if capLen == 0 || capLen > len(data) {
+		capLen = len(data)
+	}
+	if origLen == 0 {
+		origLen = len(data)
+	}
+	binary.LittleEndian.PutUint32(pw.hdr[0:4], uint32(ci.Timestamp.Unix()))
+	binary.LittleEndian.PutUint32(pw.hdr[4:8], uint32(ci.Timestamp.Nanosecond()/int(time.Microsecond)))
+	binary.LittleEndian.PutUint32(pw.hdr[8:12], uint32(capLen))
+	binary.LittleEndian.PutUint32(pw.hdr[12:16], uint32(origLen))
+	if _, err := pw.w.Write(pw.hdr[:]); err != nil {
+		return err
+	}
+	_, err := pw.w.Write(data[:capLen])
+	return err
+}
+
+// WriteFrame marshals the frame and writes it as a packet record with timestamp ts.
+func (pw *PcapWriter) WriteFrame(ts time.Time, f *Frame) error {
+	return pw.WritePacket(CaptureInfo{Timestamp: ts}, f.Marshal())
+}
+
+// WriteFrame80211 marshals the 802.11 frame and writes it as a packet record with
+// timestamp ts, the writer must be created with LinkTypeIEEE80211.
+func (pw *PcapWriter) WriteFrame80211(ts time.Time, f *Frame80211) error {
+	return pw.WritePacket(CaptureInfo{Timestamp: ts}, f.Marshal())
+}
+
+// WriteRadioTapFrame80211 writes the 802.11 frame prepended by RadioTap header rt
+// as a packet record with timestamp ts, the writer must be created with
+// LinkTypeIEEE80211RadioTap.
+func (pw *PcapWriter) WriteRadioTapFrame80211(ts time.Time, rt *RadioTap, f *Frame80211) error {
+	return pw.WritePacket(CaptureInfo{Timestamp: ts}, append(rt.Marshal(), f.Marshal()...))
+}
+
+// ReplayPcap is like ReplayPcapContext with the background context.
+func ReplayPcap(r *PcapReader, send func(*Frame) error, speed float64) error {
+	return ReplayPcapContext(context.Background(), r, send, speed)
+}
+
+// ReplayPcapContext reads frames from r and passes them to send, sleeping between the frames
+// according to the captured inter-frame delta scaled by speed (1.0 = real time, 2.0 = 2x).
+// The first frame is sent immediately, if speed <= 0 frames are sent without delays.
+// Returns nil at the end of the capture, otherwise the first error of reader, send or ctx.Err().
+func ReplayPcapContext(ctx context.Context, r *PcapReader, send func(*Frame) error, speed float64) error {
+	var prev time.Time
+	for {
+		f, ci, err := r.ReadFrame()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		if !prev.IsZero() && speed > 0 {
+			if delta := ci.Timestamp.Sub(prev); delta > 0 {
+				t := time.NewTimer(time.Duration(float64(delta) / speed))
+				select {
+				case <-ctx.Done():
+					t.Stop()
+					return ctx.Err()
+				case <-t.C:
+				}
+			}
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		prev = ci.Timestamp
+
+		if err := send(f); err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,47 @@
+// Copyright (c) 2022 0x9ef. All rights reserved.
+// Use of this source code is governed by an MIT license
+// that can be found in the LICENSE file.
+package ethernet
+
+import "encoding/binary"
+
+// PeekEtherType reads the EtherType from the raw frame without decoding it.
+// If the frame carries VLAN tags (any of VLANTPIDs), the EtherType following
+// the tags is returned. Returns false if the buffer is too short.
+func PeekEtherType(b []byte) (EtherType, bool) {
+	n := OffsetEtherType
+	for {
+		if len(b) < n+2 {
+			return 0, false
+		}
+		etype := EtherType(binary.BigEndian.Uint16(b[n:]))
+		if !isVLANTPID(uint16(etype)) {
+			return etype, true
+		}
+		n += 4
+	}
+}
+
+// PeekVLAN reads the 802.1Q tag from the raw frame without decoding it.
+// Returns false if the frame is untagged or the buffer is too short.
+func PeekVLAN(b []byte) (tpid, tci uint16, ok bool) {
+	if len(b) < OffsetTCI+2 {
+		return 0, 0, false
+	}
+	tpid = binary.BigEndian.Uint16(b[OffsetTPID:])
+	if !isVLANTPID(tpid) {
+		return 0, 0, false
+	}
+	return tpid, binary.BigEndian.Uint16(b[OffsetTCI:]), true
+}
+
+// PeekSrcDst reads the destination and source addresses from the raw frame without decoding it.
+// Returns false if the buffer is shorter than 12 bytes.
+func PeekSrcDst(b []byte) (dst, src HardwareAddr, ok bool) {
+	if len(b) < OffsetEtherType {
+		return dst, src, false
+	}
+	copy(dst[:], b[OffsetDst:OffsetSrc])
+	copy(src[:], b[OffsetSrc:OffsetEtherType])
+	return dst, src, true
+}
@@ -0,0 +1,123 @@
+package ethernet
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPeek(t *testing.T) {
+	src := HardwareAddr{127, 127, 127, 50, 50, 50}
+	dst := HardwareAddr{255, 255, 255, 50, 50, 50}
+	untagged := append([]byte(nil), NewFrame(src, dst, EtherTypeIPv6, []byte("HELLO")).Marshal()...)
+	tagged := append([]byte(nil), NewFrame(src, dst, EtherTypeIPv6, []byte("HELLO"), WithVLAN(PcpVI, 0, 100)).Marshal()...)
+
+	type suite struct {
+		name          string
+		data          []byte
+		wantEtherType EtherType
+		wantEtherOk   bool
+		wantTCI       uint16
+		wantVLANOk    bool
+	}
+
+	testCases := []suite{
+		{
+			name:          "positive_untagged",
+			data:          untagged,
+			wantEtherType: EtherTypeIPv6,
+			wantEtherOk:   true,
+		},
+		{
+			name:          "positive_tagged",
+			data:          tagged,
+			wantEtherType: EtherTypeIPv6,
+			wantEtherOk:   true,
+			wantTCI:       Encode8021qTCI(PcpVI, 0, 100),
+			wantVLANOk:    true,
+		},
+		{
+			name: "negative_truncated_header",
+			data: untagged[:13],
+		},
+		{
+			name:       "negative_truncated_tag",
+			data:       tagged[:16],
+			wantTCI:    Encode8021qTCI(PcpVI, 0, 100),
+			wantVLANOk: true,
+		},
+		{
+			name: "negative_empty",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			et, ok := PeekEtherType(tc.data)
+			assert.Equal(t, tc.wantEtherOk, ok)
+			assert.Equal(t, tc.wantEtherType, et)
+
+			tpid, tci, ok := PeekVLAN(tc.data)
+			assert.Equal(t, tc.wantVLANOk, ok)
+			assert.Equal(t, tc.wantTCI, tci)
+			if ok {
+				assert.Equal(t, uint16(EtherTypeVlan), tpid)
+			}
+		})
+	}
+}
+
+func TestPeekSrcDst(t *testing.T) {
+	src := HardwareAddr{127, 127, 127, 50, 50, 50}
+	dst := HardwareAddr{255, 255, 255, 50, 50, 50}
+	b := append([]byte(nil), NewFrame(src, dst, EtherTypeIPv6, []byte("HELLO")).Marshal()...)
+
+	type suite struct {
+		name    string
+		data    []byte
+		wantDst HardwareAddr
+		wantSrc HardwareAddr
+		wantOk  bool
+	}
+
+	testCases := []suite{
+		{
+			name:    "positive_frame",
+			data:    b,
+			wantDst: dst,
+			wantSrc: src,
+			wantOk:  true,
+		},
+		{
+			name:    "positive_addresses_only",
+			data:    b[:12],
+			wantDst: dst,
+			wantSrc: src,
+			wantOk:  true,
+		},
+		{
+			name: "negative_short",
+			data: b[:11],
+		},
+		{
+			name: "negative_empty",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			d, s, ok := PeekSrcDst(tc.data)
+			assert.Equal(t, tc.wantOk, ok)
+			assert.Equal(t, tc.wantDst, d)
+			assert.Equal(t, tc.wantSrc, s)
+		})
+	}
+}
+
+func BenchmarkPeekSrcDst(b *testing.B) {
+	data := append([]byte(nil), NewFrame(HardwareAddr{1, 2, 3, 4, 5, 6}, HardwareAddr{6, 5, 4, 3, 2, 1}, EtherTypeIPv4, generatePayload()).Marshal()...)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		PeekSrcDst(data)
+	}
+}
@@ -0,0 +1,61 @@
+// Copyright (c) 2022 0x9ef. All rights reserved.
+// Use of this source code is governed by an MIT license
+// that can be found in the LICENSE file.
+package ethernet
+
+import (
+	"encoding/binary"
+	"errors"
+	"io"
+)
+
+// ErrBadRadioTapVersion is returned when RadioTap header version is not 0.
+var ErrBadRadioTapVersion = errors.New("unsupported radiotap header version")
+
+// minRadioTapSize is version + pad + length + present
+const minRadioTapSize = 8
+
+// RadioTap is a header prepended by the capturing driver to 802.11 frames,
+// which carries the radio information (rate, channel, signal, etc).
+// All fields of the header are little-endian.
+//
+// https://www.radiotap.org
+type RadioTap struct {
+	Version uint8
+	Length  uint16 // length of the whole header, including Version, Length and Present
+	Present uint32 // first present bitmask
+	// Fields contains raw bytes following the first present bitmask,
+	// including the extended bitmasks when bit 31 of Present is set.
+	Fields []byte
+}
+
+// ParseRadioTap decodes RadioTap header from b, the 802.11 frame starts at b[Length:].
+func ParseRadioTap(b []byte) (*RadioTap, error) {
+	if len(b) < minRadioTapSize {
+		return nil, io.ErrUnexpectedEOF
+	}
+	rt := &RadioTap{
+		Version: b[0],
+		Length:  binary.LittleEndian.Uint16(b[2:4]),
+		Present: binary.LittleEndian.Uint32(b[4:8]),
+	}
+	if rt.Version != 0 {
+		return nil, ErrBadRadioTapVersion
+	}
+	if int(rt.Length) < minRadioTapSize || int(rt.Length) > len(b) {
+		return nil, io.ErrUnexpectedEOF
+	}
+	rt.Fields = b[minRadioTapSize:rt.Length]
+	return rt, nil
+}
+
+// Marshal serializes RadioTap header into the byte representation.
+// The Length is calculated from the Fields.
+func (rt *RadioTap) Marshal() []byte {
+	b := make([]byte, minRadioTapSize+len(rt.Fields))
+	b[0] = rt.Version
+	binary.LittleEndian.PutUint16(b[2:4], uint16(len(b)))
+	binary.LittleEndian.PutUint32(b[4:8], rt.Present)
+	copy(b[minRadioTapSize:], rt.Fields)
+	return b
+}
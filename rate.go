@@ -0,0 +1,33 @@
+// Copyright (c) 2022 0x9ef. All rights reserved.
+// Use of this source code is governed by an MIT license
+// that can be found in the LICENSE file.
+package ethernet
+
+// Rate is the line rate of the link in bits per second.
+type Rate uint64
+
+// Common line rates.
+const (
+	Kbps Rate = 1000
+	Mbps      = 1000 * Kbps
+	Gbps      = 1000 * Mbps
+)
+
+const (
+	preambleSize = 8  // preamble (7) + SFD (1)
+	ifgSize      = 12 // minimum interframe gap
+)
+
+// FramesPerSecond returns the theoretical maximum number of frames per second at the line rate,
+// for frames of frameSize bytes including FCS (see Frame.Size). Frames shorter than MinFrameSize
+// are counted as padded, each frame is accompanied by preamble, SFD and interframe gap on the wire,
+// e.g. 64-byte frames at 1 Gbit/s are sent at ~1.488 Mpps. Returns 0 if the rate is 0.
+func (r Rate) FramesPerSecond(frameSize int) float64 {
+	if r == 0 {
+		return 0
+	}
+	if frameSize < MinFrameSize {
+		frameSize = MinFrameSize
+	}
+	return float64(r) / float64((frameSize+preambleSize+ifgSize)*8)
+}
@@ -0,0 +1,30 @@
+package ethernet
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRateFramesPerSecond(t *testing.T) {
+	type suite struct {
+		name      string
+		rate      Rate
+		frameSize int
+		want      float64
+	}
+
+	testCases := []suite{
+		{name: "positive_1g_min_frame", rate: Gbps, frameSize: MinFrameSize, want: 1488095.238},
+		{name: "positive_10g_min_frame", rate: 10 * Gbps, frameSize: MinFrameSize, want: 14880952.38},
+		{name: "positive_1g_max_frame", rate: Gbps, frameSize: MaxFrameSize, want: 81274.382},
+		{name: "positive_runt_padded", rate: Gbps, frameSize: 20, want: 1488095.238},
+		{name: "negative_zero_rate", rate: 0, frameSize: MinFrameSize, want: 0},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.InDelta(t, tc.want, tc.rate.FramesPerSecond(tc.frameSize), 0.01)
+		})
+	}
+}
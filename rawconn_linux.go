@@ -0,0 +1,110 @@
+// Copyright (c) 2022 0x9ef. All rights reserved.
+// Use of this source code is governed by an MIT license
+// that can be found in the LICENSE file.
+
+//go:build linux
+// +build linux
+
+package ethernet
+
+import (
+	"encoding/binary"
+	"net"
+	"syscall"
+	"unsafe"
+)
+
+// Packet types reported by the kernel in sockaddr_ll.sll_pkttype,
+// they describe how the frame reached the socket.
+const (
+	PacketHost      uint8 = syscall.PACKET_HOST      // addressed to the local host
+	PacketBroadcast uint8 = syscall.PACKET_BROADCAST // link layer broadcast
+	PacketMulticast uint8 = syscall.PACKET_MULTICAST // link layer multicast
+	PacketOtherHost uint8 = syscall.PACKET_OTHERHOST // addressed to another host (promiscuous mode)
+	PacketOutgoing  uint8 = syscall.PACKET_OUTGOING  // originated from the local host
+)
+
+// ethPAll is htons(ETH_P_ALL), receives frames of all protocols.
+var ethPAll = htons(syscall.ETH_P_ALL)
+
+// htons converts v from host to network byte order.
+func htons(v uint16) uint16 {
+	var b [2]byte
+	binary.BigEndian.PutUint16(b[:], v)
+	return *(*uint16)(unsafe.Pointer(&b[0]))
+}
+
+// PacketMeta is the metadata of the received frame from sockaddr_ll.
+type PacketMeta struct {
+	IfIndex int   // index of the interface the frame was received on
+	PktType uint8 // one of Packet* constants
+}
+
+// RawConn is an AF_PACKET raw socket bound to a network interface.
+// Linux only, requires CAP_NET_RAW.
+type RawConn struct {
+	fd      int
+	ifIndex int
+	buf     []byte
+}
+
+// ListenRaw opens a raw socket receiving frames of all protocols on the interface ifName.
+func ListenRaw(ifName string) (*RawConn, error) {
+	ifi, err := net.InterfaceByName(ifName)
+	if err != nil {
+		return nil, err
+	}
+	fd, err := syscall.Socket(syscall.AF_PACKET, syscall.SOCK_RAW, int(ethPAll))
+	if err != nil {
+		return nil, err
+	}
+	if err := syscall.Bind(fd, &syscall.SockaddrLinklayer{Protocol: ethPAll, Ifindex: ifi.Index}); err != nil {
+		syscall.Close(fd)
+		return nil, err
+	}
+	return &RawConn{fd: fd, ifIndex: ifi.Index, buf: make([]byte, 65536)}, nil
+}
+
+// ReadFrame reads the next frame, see ReadFrameMeta.
+func (c *RawConn) ReadFrame() (*Frame, error) {
+	f, _, err := c.ReadFrameMeta()
+	return f, err
+}
+
+// ReadFrameMeta reads the next frame with the interface index and the packet type,
+// so locally originated frames (PacketOutgoing) can be distinguished from received ones.
+// The kernel strips the FCS, so the frame is decoded with NoFCS and its FCS is zero.
+func (c *RawConn) ReadFrameMeta() (*Frame, PacketMeta, error) {
+	n, from, err := syscall.Recvfrom(c.fd, c.buf, 0)
+	if err != nil {
+		return nil, PacketMeta{}, err
+	}
+	var meta PacketMeta
+	if sll, ok := from.(*syscall.SockaddrLinklayer); ok {
+		meta.IfIndex = sll.Ifindex
+		meta.PktType = sll.Pkttype
+	}
+
+	f := new(Frame)
+	if err := (UnmarshalOptions{NoFCS: true}).Unmarshal(append([]byte(nil), c.buf[:n]...), f); err != nil {
+		return nil, meta, err
+	}
+	return f, meta, nil
+}
+
+// WriteFrame sends the frame on the interface, the FCS is appended by the NIC.
+func (c *RawConn) WriteFrame(f *Frame) error {
+	b := f.Marshal()
+	to := &syscall.SockaddrLinklayer{
+		Protocol: ethPAll,
+		Ifindex:  c.ifIndex,
+		Halen:    6,
+	}
+	copy(to.Addr[:], f.dst[:])
+	return syscall.Sendto(c.fd, b[:len(b)-4], 0, to)
+}
+
+// Close closes the socket.
+func (c *RawConn) Close() error {
+	return syscall.Close(c.fd)
+}
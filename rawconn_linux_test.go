@@ -0,0 +1,49 @@
+//go:build linux
+// +build linux
+
+package ethernet
+
+import (
+	"syscall"
+	"testing"
+	"unsafe"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRawConnReadFrameMeta(t *testing.T) {
+	c, err := ListenRaw("lo")
+	if err != nil {
+		t.Skipf("raw socket is not available: %v", err)
+	}
+	defer c.Close()
+	tv := syscall.Timeval{Sec: 2}
+	assert.NoError(t, syscall.SetsockoptTimeval(c.fd, syscall.SOL_SOCKET, syscall.SO_RCVTIMEO, &tv))
+
+	src := HardwareAddr{0x02, 0x00, 0x00, 0x00, 0x00, 0x01}
+	dst := HardwareAddr{0x02, 0x00, 0x00, 0x00, 0x00, 0x02}
+	f := NewFrame(src, dst, EtherType(0x88B5), []byte("HELLO"))
+	assert.NoError(t, c.WriteFrame(f))
+
+	for i := 0; i < 16; i++ {
+		decoded, meta, err := c.ReadFrameMeta()
+		if !assert.NoError(t, err) {
+			return
+		}
+		if decoded.Source() != src || decoded.EtherType() != EtherType(0x88B5) {
+			continue
+		}
+		assert.Equal(t, c.ifIndex, meta.IfIndex)
+		assert.Contains(t, []uint8{PacketHost, PacketOtherHost, PacketOutgoing}, meta.PktType)
+		assert.Equal(t, f.Payload(), decoded.Payload())
+		assert.Equal(t, [4]byte{}, decoded.FCS())
+		return
+	}
+	t.Fatal("sent frame was not received")
+}
+
+func TestHtons(t *testing.T) {
+	// in memory ETH_P_ALL must be laid out in network byte order
+	b := (*[2]byte)(unsafe.Pointer(&ethPAll))
+	assert.Equal(t, [2]byte{0x00, 0x03}, *b)
+}
@@ -0,0 +1,236 @@
+// Copyright (c) 2022 0x9ef. All rights reserved.
+// Use of this source code is governed by an MIT license
+// that can be found in the LICENSE file.
+package ethernet
+
+import (
+	"compress/gzip"
+	"context"
+	"encoding/binary"
+	"errors"
+	"io"
+	"io/ioutil"
+	"time"
+)
+
+var (
+	// ErrBadLenPrefix is returned when the length prefix width is not 2 or 4 bytes.
+	ErrBadLenPrefix = errors.New("length prefix must be 2 or 4 bytes")
+	// ErrRecordTooLong is returned when the length prefix exceeds maxRecordSize.
+	ErrRecordTooLong = errors.New("length-prefixed record is too long")
+)
+
+// maxRecordSize is the maximum size of the length-prefixed record, so a corrupted
+// length prefix doesn't cause a huge allocation. It matches the pcap snaplen limit.
+const maxRecordSize = pcapMaxSnaplen
+
+// FrameReader reads length-delimited Ethernet frames from the underlying reader.
+// Each frame is preceded by a big-endian length prefix of lenPrefix bytes,
+// which holds the size of the serialized frame (including FCS).
+//
+// Some capture formats align records (length prefix + frame) to a boundary
+// with trailing padding, see SetAlignment.
+type FrameReader struct {
+	r         io.Reader
+	lenPrefix int
+	align     int
+	pad       int // padding to skip before the next record
+	snaplen   int
+	hdr       [4]byte
+}
+
+// NewFrameReader returns a new FrameReader reading from r, lenPrefix
+// is the width of the length prefix in bytes (2 or 4).
+func NewFrameReader(r io.Reader, lenPrefix int) (*FrameReader, error) {
+	if lenPrefix != 2 && lenPrefix != 4 {
+		return nil, ErrBadLenPrefix
+	}
+	return &FrameReader{r: r, lenPrefix: lenPrefix}, nil
+}
+
+// SetAlignment sets the alignment of records in bytes. Each record (length prefix + frame)
+// is followed by padding, so the next record starts at a multiple of align bytes
+// from the start of the first record. The padding after the last record may be omitted.
+// The default alignment of 0 (or 1) means no padding.
+func (fr *FrameReader) SetAlignment(align int) { fr.align = align }
+
+// SetSnaplen sets the maximum number of bytes of the frame to decode, longer frames
+// are truncated (see Frame.IsTruncated). The default of 0 means no limit.
+func (fr *FrameReader) SetSnaplen(snaplen int) { fr.snaplen = snaplen }
+
+// OpenFrameReader reads and validates the format header written by FrameWriter,
+// and returns a new FrameReader with the length prefix width stored in the header.
+// The compressed records are decompressed transparently.
+// Returns ErrBadMagic if the stream doesn't start with the header,
+// ErrUnsupportedVersion if the format version is unknown, and
+// ErrUnsupportedCompression if the compression is unknown.
+func OpenFrameReader(r io.Reader) (*FrameReader, error) {
+	var hdr [frameLogHeaderSize]byte
+	if _, err := io.ReadFull(r, hdr[:]); err != nil {
+		return nil, err
+	}
+	if hdr[0] != frameLogMagic[0] || hdr[1] != frameLogMagic[1] || hdr[2] != frameLogMagic[2] {
+		return nil, ErrBadMagic
+	}
+	if hdr[3] != frameLogVersion {
+		return nil, ErrUnsupportedVersion
+	}
+	lenPrefix := int(hdr[4])
+	if lenPrefix != 2 && lenPrefix != 4 {
+		return nil, ErrBadLenPrefix
+	}
+	switch Compression(hdr[5]) {
+	case CompressNone:
+	case CompressGzip:
+		zr, err := gzip.NewReader(r)
+		if err != nil {
+			return nil, err
+		}
+		r = zr
+	default:
+		return nil, ErrUnsupportedCompression
+	}
+	return NewFrameReader(r, lenPrefix)
+}
+
+// ReadFrame reads and decodes the next frame. The returned frame owns its buffer.
+// Returns io.EOF when the stream ends on a frame boundary, and ErrRecordTooLong
+// if the length prefix exceeds 262144 bytes.
+func (fr *FrameReader) ReadFrame() (*Frame, error) {
+	if fr.pad > 0 {
+		if _, err := io.CopyN(ioutil.Discard, fr.r, int64(fr.pad)); err != nil {
+			return nil, err
+		}
+		fr.pad = 0
+	}
+	b, err := readLenPrefixed(fr.r, fr.hdr[:fr.lenPrefix])
+	if err != nil {
+		return nil, err
+	}
+	if fr.align > 1 {
+		fr.pad = (fr.align - (fr.lenPrefix+len(b))%fr.align) % fr.align
+	}
+	f := new(Frame)
+	if fr.snaplen > 0 && len(b) > fr.snaplen {
+		err = unmarshalTruncated(b[:fr.snaplen], len(b), f)
+	} else {
+		err = Unmarshal(b, f)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+// readLenPrefixed reads the length prefix into hdr and returns a new buffer
+// with the record. Returns io.EOF only if the stream ends on a record boundary,
+// and ErrRecordTooLong if the length prefix exceeds maxRecordSize.
+func readLenPrefixed(r io.Reader, hdr []byte) ([]byte, error) {
+	return readLenPrefixedBuf(r, hdr, nil)
+}
+
+// readLenPrefixedBuf is like readLenPrefixed, but reads the record into buf
+// if it has enough capacity.
+func readLenPrefixedBuf(r io.Reader, hdr []byte, buf []byte) ([]byte, error) {
+	if _, err := io.ReadFull(r, hdr); err != nil {
+		return nil, err
+	}
+	var sz int
+	if len(hdr) == 2 {
+		sz = int(binary.BigEndian.Uint16(hdr))
+	} else {
+		sz32 := binary.BigEndian.Uint32(hdr)
+		if sz32 > maxRecordSize {
+			return nil, ErrRecordTooLong
+		}
+		sz = int(sz32)
+	}
+
+	b := buf[:0]
+	if cap(b) < sz {
+		b = make([]byte, sz)
+	}
+	b = b[:sz]
+	if _, err := io.ReadFull(r, b); err != nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return nil, err
+	}
+	return b, nil
+}
+
+// VerifyStream reads length-delimited frames (see FrameReader) from r until the end of
+// the stream and counts the frames whose FCS doesn't match (see VerifyFCS). The records
+// are read into a single reused buffer and are not decoded. Returns the counts with nil
+// error when the stream ends on a record boundary, otherwise the counts of the frames
+// read before the first error.
+func VerifyStream(r io.Reader, lenPrefix int) (total, bad uint64, err error) {
+	if lenPrefix != 2 && lenPrefix != 4 {
+		return 0, 0, ErrBadLenPrefix
+	}
+	var hdr [4]byte
+	buf := make([]byte, MaxFrameSize)
+	for {
+		b, err := readLenPrefixedBuf(r, hdr[:lenPrefix], buf)
+		if err == io.EOF {
+			return total, bad, nil
+		}
+		if err != nil {
+			return total, bad, err
+		}
+		if cap(b) > cap(buf) {
+			buf = b
+		}
+		total++
+		if !VerifyFCS(b) {
+			bad++
+		}
+	}
+}
+
+// deadlineReader is implemented by readers which support read deadlines, e.g. net.Conn.
+type deadlineReader interface {
+	SetReadDeadline(t time.Time) error
+}
+
+// ReadContext reads the next frame like ReadFrame, but aborts when ctx is cancelled
+// and returns ctx.Err(). A blocked read is interrupted only when the underlying
+// reader supports read deadlines (e.g. net.Conn), otherwise the cancellation
+// takes effect only between frames. A frame interrupted in the middle of a read
+// is lost, and the stream may be left out of sync. A frame read before the
+// cancellation is observed is returned with nil error.
+func (fr *FrameReader) ReadContext(ctx context.Context) (*Frame, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	dr, ok := fr.r.(deadlineReader)
+	if !ok {
+		return fr.ReadFrame()
+	}
+
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		select {
+		case <-ctx.Done():
+			// unblock the pending read immediately
+			dr.SetReadDeadline(time.Unix(1, 0))
+		case <-done:
+		}
+	}()
+
+	f, err := fr.ReadFrame()
+	close(done)
+	<-stopped
+	ctxErr := ctx.Err()
+	if ctxErr != nil {
+		// reset the deadline, so the reader can be used after cancellation
+		dr.SetReadDeadline(time.Time{})
+	}
+	if err != nil && ctxErr != nil {
+		return nil, ctxErr
+	}
+	return f, err
+}
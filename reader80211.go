@@ -0,0 +1,53 @@
+// Copyright (c) 2022 0x9ef. All rights reserved.
+// Use of this source code is governed by an MIT license
+// that can be found in the LICENSE file.
+package ethernet
+
+import "io"
+
+// Reader80211 reads length-delimited 802.11 frames from the underlying reader,
+// it's the counterpart to the FrameReader for wireless captures.
+// Each record may start with a RadioTap header, see SetRadioTap.
+type Reader80211 struct {
+	r         io.Reader
+	lenPrefix int
+	radioTap  bool
+	hdr       [4]byte
+}
+
+// NewReader80211 returns a new Reader80211 reading from r, lenPrefix is
+// the width of the length prefix in bytes (2 or 4). If radioTap is true,
+// each record is expected to start with a RadioTap header.
+func NewReader80211(r io.Reader, lenPrefix int, radioTap bool) (*Reader80211, error) {
+	if lenPrefix != 2 && lenPrefix != 4 {
+		return nil, ErrBadLenPrefix
+	}
+	return &Reader80211{r: r, lenPrefix: lenPrefix, radioTap: radioTap}, nil
+}
+
+// SetRadioTap toggles whether records start with a RadioTap header.
+func (fr *Reader80211) SetRadioTap(radioTap bool) { fr.radioTap = radioTap }
+
+// Read reads and decodes the next frame. The RadioTap is nil if the RadioTap mode is off.
+// Returns io.EOF when the stream ends on a frame boundary, and ErrRecordTooLong
+// if the length prefix exceeds 262144 bytes.
+func (fr *Reader80211) Read() (*Frame80211, *RadioTap, error) {
+	b, err := readLenPrefixed(fr.r, fr.hdr[:fr.lenPrefix])
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var rt *RadioTap
+	if fr.radioTap {
+		rt, err = ParseRadioTap(b)
+		if err != nil {
+			return nil, nil, err
+		}
+		b = b[rt.Length:]
+	}
+	f, err := Unmarshal80211(b)
+	if err != nil {
+		return nil, nil, err
+	}
+	return f, rt, nil
+}
@@ -0,0 +1,57 @@
+package ethernet
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReader80211Read(t *testing.T) {
+	f := NewFrame80211(HardwareAddr{127, 127, 127, 50, 50, 50}, HardwareAddr{255, 255, 255, 50, 50, 50}, HardwareAddr{255, 255, 255, 50, 50, 20}, nil, 0x08, 0x10, []byte("HELLO"))
+	rt := &RadioTap{Present: 0x2, Fields: []byte{0x10}}
+
+	type suite struct {
+		name     string
+		radioTap bool
+		record   []byte
+	}
+
+	testCases := []suite{
+		{
+			name:   "positive_no_radiotap",
+			record: append([]byte(nil), f.Marshal()...),
+		},
+		{
+			name:     "positive_radiotap",
+			radioTap: true,
+			record:   append(rt.Marshal(), f.Marshal()...),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			writeLenPrefixed(&buf, tc.record)
+
+			r, err := NewReader80211(&buf, 2, tc.radioTap)
+			assert.NoError(t, err)
+			decoded, decodedRt, err := r.Read()
+			assert.NoError(t, err)
+			assert.Equal(t, f.Receiver(), decoded.Receiver())
+			assert.Equal(t, f.Transmitter(), decoded.Transmitter())
+			assert.Equal(t, []byte("HELLO"), decoded.Payload())
+			if tc.radioTap {
+				assert.Equal(t, uint16(9), decodedRt.Length)
+				assert.Equal(t, rt.Present, decodedRt.Present)
+				assert.Equal(t, rt.Fields, decodedRt.Fields)
+			} else {
+				assert.Nil(t, decodedRt)
+			}
+
+			_, _, err = r.Read()
+			assert.Equal(t, io.EOF, err)
+		})
+	}
+}
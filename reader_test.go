@@ -0,0 +1,238 @@
+package ethernet
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func writeLenPrefixed(buf *bytes.Buffer, b []byte) {
+	var hdr [2]byte
+	binary.BigEndian.PutUint16(hdr[:], uint16(len(b)))
+	buf.Write(hdr[:])
+	buf.Write(b)
+}
+
+func TestFrameReaderReadFrame(t *testing.T) {
+	var buf bytes.Buffer
+	f1 := NewFrame(HardwareAddr{127, 127, 127, 50, 50, 50}, HardwareAddr{255, 255, 255, 50, 50, 50}, EtherTypeIPv4, []byte("HELLO"))
+	f2 := NewFrame(HardwareAddr{127, 127, 127, 50, 50, 51}, HardwareAddr{255, 255, 255, 50, 50, 51}, EtherTypeIPv6, []byte("WORLD"))
+	writeLenPrefixed(&buf, append([]byte(nil), f1.Marshal()...))
+	writeLenPrefixed(&buf, append([]byte(nil), f2.Marshal()...))
+
+	fr, err := NewFrameReader(&buf, 2)
+	assert.NoError(t, err)
+
+	f, err := fr.ReadFrame()
+	assert.NoError(t, err)
+	assert.Equal(t, f1.Source(), f.Source())
+	f, err = fr.ReadFrame()
+	assert.NoError(t, err)
+	assert.Equal(t, EtherTypeIPv6, f.EtherType())
+	_, err = fr.ReadFrame()
+	assert.Equal(t, io.EOF, err)
+}
+
+func TestFrameReaderSnaplen(t *testing.T) {
+	var buf bytes.Buffer
+	long := NewFrame(HardwareAddr{127, 127, 127, 50, 50, 50}, HardwareAddr{255, 255, 255, 50, 50, 50}, EtherTypeIPv4, generatePayload())
+	short := NewFrame(HardwareAddr{127, 127, 127, 50, 50, 51}, HardwareAddr{255, 255, 255, 50, 50, 51}, EtherTypeIPv6, []byte("WORLD"))
+	writeLenPrefixed(&buf, append([]byte(nil), long.Marshal()...))
+	writeLenPrefixed(&buf, append([]byte(nil), short.Marshal()...))
+
+	fr, err := NewFrameReader(&buf, 2)
+	assert.NoError(t, err)
+	fr.SetSnaplen(100)
+
+	f, err := fr.ReadFrame()
+	assert.NoError(t, err)
+	assert.True(t, f.IsTruncated())
+	captured, original := f.CaptureLength()
+	assert.Equal(t, 100, captured)
+	assert.Equal(t, long.Size(), original)
+	assert.Equal(t, long.Payload()[:100-OffsetPayload], f.Payload())
+
+	f, err = fr.ReadFrame()
+	assert.NoError(t, err)
+	assert.False(t, f.IsTruncated())
+	assert.Equal(t, short.Payload(), f.Payload())
+}
+
+func TestFrameReaderReadContext(t *testing.T) {
+	client, server := net.Pipe()
+	defer client.Close()
+	defer server.Close()
+
+	fr, err := NewFrameReader(client, 2)
+	assert.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		cancel()
+	}()
+	_, err = fr.ReadContext(ctx)
+	assert.Equal(t, context.Canceled, err)
+
+	_, err = fr.ReadContext(ctx)
+	assert.Equal(t, context.Canceled, err)
+}
+
+// cancelReader cancels the context on the first read, as if the cancellation
+// raced with the read, which still succeeds.
+type cancelReader struct {
+	r      io.Reader
+	cancel context.CancelFunc
+}
+
+func (r *cancelReader) Read(p []byte) (int, error) {
+	r.cancel()
+	return r.r.Read(p)
+}
+
+func (r *cancelReader) SetReadDeadline(time.Time) error { return nil }
+
+func TestFrameReaderReadContextCancelAfterRead(t *testing.T) {
+	var buf bytes.Buffer
+	f1 := NewFrame(HardwareAddr{127, 127, 127, 50, 50, 50}, HardwareAddr{255, 255, 255, 50, 50, 50}, EtherTypeIPv4, []byte("HELLO"))
+	writeLenPrefixed(&buf, append([]byte(nil), f1.Marshal()...))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	fr, err := NewFrameReader(&cancelReader{r: &buf, cancel: cancel}, 2)
+	assert.NoError(t, err)
+
+	// the frame read before the cancellation is not lost
+	f, err := fr.ReadContext(ctx)
+	assert.NoError(t, err)
+	if assert.NotNil(t, f) {
+		assert.Equal(t, f1.Payload(), f.Payload())
+	}
+
+	_, err = fr.ReadContext(ctx)
+	assert.Equal(t, context.Canceled, err)
+}
+
+func TestFrameReaderReadContextNoDeadline(t *testing.T) {
+	var buf bytes.Buffer
+	f1 := NewFrame(HardwareAddr{127, 127, 127, 50, 50, 50}, HardwareAddr{255, 255, 255, 50, 50, 50}, EtherTypeIPv4, []byte("HELLO"))
+	writeLenPrefixed(&buf, append([]byte(nil), f1.Marshal()...))
+
+	fr, err := NewFrameReader(&buf, 2)
+	assert.NoError(t, err)
+	f, err := fr.ReadContext(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, f1.Destination(), f.Destination())
+}
+
+func TestFrameReaderAlignment(t *testing.T) {
+	var buf bytes.Buffer
+	var want []*Frame
+	for i, n := range []int{5, 47, 49} {
+		f := NewFrame(HardwareAddr{127, 127, 127, 50, 50, byte(i)}, HardwareAddr{255, 255, 255, 50, 50, 50}, EtherTypeIPv4, make([]byte, n))
+		want = append(want, f)
+		writeLenPrefixed(&buf, append([]byte(nil), f.Marshal()...))
+		if i < 2 {
+			for buf.Len()%4 != 0 {
+				buf.WriteByte(0xFF)
+			}
+		}
+	}
+
+	fr, err := NewFrameReader(&buf, 2)
+	assert.NoError(t, err)
+	fr.SetAlignment(4)
+	for _, f := range want {
+		decoded, err := fr.ReadFrame()
+		assert.NoError(t, err)
+		assert.Equal(t, f.Source(), decoded.Source())
+		assert.Equal(t, f.Payload(), decoded.Payload())
+	}
+	_, err = fr.ReadFrame()
+	assert.Equal(t, io.EOF, err)
+}
+
+func TestReadLenPrefixedTooLong(t *testing.T) {
+	type suite struct {
+		name    string
+		data    []byte
+		wantErr error
+	}
+
+	testCases := []suite{
+		{name: "positive_max", data: []byte{0x00, 0x04, 0x00, 0x00, 1, 2, 3}, wantErr: io.ErrUnexpectedEOF},
+		{name: "negative_above_max", data: []byte{0x00, 0x04, 0x00, 0x01, 1, 2, 3}, wantErr: ErrRecordTooLong},
+		{name: "negative_256mib", data: []byte{0x10, 0x00, 0x00, 0x00, 1, 2, 3}, wantErr: ErrRecordTooLong},
+		{name: "negative_4gib", data: []byte{0xFF, 0xFF, 0xFF, 0xFF, 1, 2, 3}, wantErr: ErrRecordTooLong},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			fr, err := NewFrameReader(bytes.NewReader(tc.data), 4)
+			assert.NoError(t, err)
+			_, err = fr.ReadFrame()
+			assert.Equal(t, tc.wantErr, err)
+
+			_, _, err = VerifyStream(bytes.NewReader(tc.data), 4)
+			assert.Equal(t, tc.wantErr, err)
+
+			r, err := NewReader80211(bytes.NewReader(tc.data), 4, false)
+			assert.NoError(t, err)
+			_, _, err = r.Read()
+			assert.Equal(t, tc.wantErr, err)
+		})
+	}
+}
+
+func TestVerifyStream(t *testing.T) {
+	var buf bytes.Buffer
+	for i := 0; i < 5; i++ {
+		b := append([]byte(nil), NewFrame(HardwareAddr{127, 127, 127, 50, 50, byte(i)}, HardwareAddr{255, 255, 255, 50, 50, 50}, EtherTypeIPv4, []byte("HELLO")).Marshal()...)
+		if i%2 == 1 {
+			b[len(b)-1] ^= 0xFF // corrupt FCS
+		}
+		writeLenPrefixed(&buf, b)
+	}
+	stream := buf.Bytes()
+
+	type suite struct {
+		name      string
+		stream    []byte
+		lenPrefix int
+		wantTotal uint64
+		wantBad   uint64
+		wantErr   error
+	}
+
+	testCases := []suite{
+		{name: "positive_mixed", stream: stream, lenPrefix: 2, wantTotal: 5, wantBad: 2},
+		{name: "positive_empty", stream: nil, lenPrefix: 2},
+		{name: "negative_truncated", stream: stream[:len(stream)-1], lenPrefix: 2, wantTotal: 4, wantBad: 2, wantErr: io.ErrUnexpectedEOF},
+		{name: "negative_bad_len_prefix", stream: stream, lenPrefix: 3, wantErr: ErrBadLenPrefix},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			total, bad, err := VerifyStream(bytes.NewReader(tc.stream), tc.lenPrefix)
+			assert.Equal(t, tc.wantErr, err)
+			assert.Equal(t, tc.wantTotal, total)
+			assert.Equal(t, tc.wantBad, bad)
+		})
+	}
+
+	// the buffers are allocated once per stream, not per frame
+	allocs := func(stream []byte) float64 {
+		r := bytes.NewReader(stream)
+		return testing.AllocsPerRun(10, func() {
+			r.Reset(stream)
+			VerifyStream(r, 2)
+		})
+	}
+	first := binary.BigEndian.Uint16(stream[:2])
+	assert.Equal(t, allocs(stream[:2+first]), allocs(stream))
+}
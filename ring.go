@@ -0,0 +1,103 @@
+// Copyright (c) 2022 0x9ef. All rights reserved.
+// Use of this source code is governed by an MIT license
+// that can be found in the LICENSE file.
+package ethernet
+
+import (
+	"io"
+	"sync"
+	"time"
+)
+
+type ringEntry struct {
+	ts    time.Time
+	frame *Frame
+}
+
+// FrameRing is a fixed capacity in-memory ring of the most recent frames with
+// their capture timestamps, when full the oldest frame is overwritten.
+// It's safe for concurrent use.
+type FrameRing struct {
+	mu      sync.Mutex
+	entries []ringEntry
+	next    int  // index of the next entry to write
+	full    bool // all entries are written at least once
+	now     func() time.Time
+}
+
+// NewFrameRing returns a new FrameRing holding up to size frames.
+func NewFrameRing(size int) *FrameRing {
+	return &FrameRing{entries: make([]ringEntry, size), now: time.Now}
+}
+
+// Push stores a copy of the frame with the current time as its timestamp.
+func (r *FrameRing) Push(f *Frame) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if len(r.entries) == 0 {
+		return
+	}
+	e := &r.entries[r.next]
+	if e.frame == nil {
+		e.frame = new(Frame)
+	}
+	f.CopyTo(e.frame)
+	e.ts = r.now()
+	r.next++
+	if r.next == len(r.entries) {
+		r.next, r.full = 0, true
+	}
+}
+
+// Len returns the number of held frames.
+func (r *FrameRing) Len() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.full {
+		return len(r.entries)
+	}
+	return r.next
+}
+
+// each calls fn for the held entries from the oldest to the newest, r.mu must be held.
+func (r *FrameRing) each(fn func(e ringEntry) error) error {
+	start, n := 0, r.next
+	if r.full {
+		start, n = r.next, len(r.entries)
+	}
+	for i := 0; i < n; i++ {
+		if err := fn(r.entries[(start+i)%len(r.entries)]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Frames returns copies of the held frames from the oldest to the newest.
+func (r *FrameRing) Frames() []*Frame {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var frames []*Frame
+	r.each(func(e ringEntry) error {
+		f := new(Frame)
+		e.frame.CopyTo(f)
+		frames = append(frames, f)
+		return nil
+	})
+	return frames
+}
+
+// WritePcap dumps the held frames from the oldest to the newest to w as a pcap
+// stream of LinkTypeEthernet with the timestamps stored on Push. The ring is not
+// modified, so it can be dumped repeatedly (e.g. on crash or on demand).
+func (r *FrameRing) WritePcap(w io.Writer) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	pw, err := NewPcapWriter(w, LinkTypeEthernet)
+	if err != nil {
+		return err
+	}
+	return r.each(func(e ringEntry) error {
+		return pw.WritePacket(CaptureInfo{Timestamp: e.ts}, e.frame.MarshalReadOnly())
+	})
+}
@@ -0,0 +1,76 @@
+package ethernet
+
+import (
+	"bytes"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFrameRingWritePcap(t *testing.T) {
+	src := HardwareAddr{127, 127, 127, 50, 50, 50}
+	dst := HardwareAddr{255, 255, 255, 50, 50, 50}
+
+	type suite struct {
+		name        string
+		size        int
+		pushed      int
+		wantPayload []string
+	}
+
+	testCases := []suite{
+		{name: "positive_empty", size: 3},
+		{name: "positive_partial", size: 3, pushed: 2, wantPayload: []string{"0", "1"}},
+		{name: "positive_full", size: 3, pushed: 3, wantPayload: []string{"0", "1", "2"}},
+		{name: "positive_wrapped", size: 3, pushed: 5, wantPayload: []string{"2", "3", "4"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ts := time.Unix(1600000000, 0).UTC()
+			r := NewFrameRing(tc.size)
+			r.now = func() time.Time { return ts }
+			for i := 0; i < tc.pushed; i++ {
+				r.Push(NewFrame(src, dst, EtherTypeIPv4, []byte{'0' + byte(i)}))
+				ts = ts.Add(time.Millisecond)
+			}
+			assert.Equal(t, len(tc.wantPayload), r.Len())
+
+			var buf bytes.Buffer
+			assert.NoError(t, r.WritePcap(&buf))
+
+			pr, err := NewPcapReader(&buf)
+			if !assert.NoError(t, err) {
+				return
+			}
+			assert.Equal(t, LinkTypeEthernet, pr.LinkType())
+			var prev time.Time
+			for _, want := range tc.wantPayload {
+				f, ci, err := pr.ReadFrame()
+				if !assert.NoError(t, err) {
+					return
+				}
+				assert.Equal(t, want, string(f.Payload()[:1]))
+				assert.True(t, f.VerifyFCS())
+				assert.True(t, ci.Timestamp.After(prev))
+				prev = ci.Timestamp
+			}
+			_, _, err = pr.ReadFrame()
+			assert.Equal(t, io.EOF, err)
+		})
+	}
+}
+
+func TestFrameRingCopiesFrames(t *testing.T) {
+	payload := []byte("HELLO")
+	f := NewFrame(HardwareAddr{}, HardwareAddr{}, EtherTypeIPv4, payload, WithRawPayload())
+	r := NewFrameRing(1)
+	r.Push(f)
+	payload[0] = 'J'
+	frames := r.Frames()
+	if assert.Len(t, frames, 1) {
+		assert.Equal(t, []byte("HELLO"), frames[0].Payload())
+	}
+}
@@ -0,0 +1,55 @@
+// Copyright (c) 2022 0x9ef. All rights reserved.
+// Use of this source code is governed by an MIT license
+// that can be found in the LICENSE file.
+package ethernet
+
+// Stats accumulates frame counters of a capture loop.
+// Stats is not safe for concurrent use, calls from multiple goroutines
+// must be guarded by an external lock.
+type Stats struct {
+	Frames      uint64
+	Bytes       uint64
+	Tagged      uint64
+	Runts       uint64 // frames smaller than MinFrameSize
+	BadFCS      uint64
+	ByEtherType map[EtherType]uint64
+}
+
+// Observe counts the decoded frame. The FCS cannot be verified on a decoded frame,
+// use ObserveBytes to count frames with bad FCS.
+func (s *Stats) Observe(f *Frame) {
+	s.observe(f, f.Size())
+}
+
+// ObserveBytes decodes and counts the raw frame, verifying its FCS.
+// Frames which are too short to be decoded are counted as runts,
+// and the decoding error is returned.
+func (s *Stats) ObserveBytes(b []byte) error {
+	var f Frame
+	if err := Unmarshal(b, &f); err != nil {
+		s.Frames++
+		s.Bytes += uint64(len(b))
+		s.Runts++
+		return err
+	}
+	if !VerifyFCS(b) {
+		s.BadFCS++
+	}
+	s.observe(&f, len(b))
+	return nil
+}
+
+func (s *Stats) observe(f *Frame, sz int) {
+	s.Frames++
+	s.Bytes += uint64(sz)
+	if f.tag8021q != nil {
+		s.Tagged++
+	}
+	if sz < MinFrameSize {
+		s.Runts++
+	}
+	if s.ByEtherType == nil {
+		s.ByEtherType = make(map[EtherType]uint64)
+	}
+	s.ByEtherType[f.etherType]++
+}
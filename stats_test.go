@@ -0,0 +1,35 @@
+package ethernet
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStats(t *testing.T) {
+	src := HardwareAddr{127, 127, 127, 50, 50, 50}
+	dst := HardwareAddr{255, 255, 255, 50, 50, 50}
+
+	var s Stats
+	s.Observe(NewFrame(src, dst, EtherTypeIPv4, []byte("HELLO")))
+	s.Observe(NewFrame(src, dst, EtherTypeIPv6, []byte("HELLO"), WithVLAN(PcpVI, 0, 100)))
+	s.Observe(NewFrame(src, dst, EtherTypeARP, []byte("HELLO"), WithRawPayload()))
+
+	good := append([]byte(nil), NewFrame(src, dst, EtherTypeIPv4, []byte("HELLO")).Marshal()...)
+	assert.NoError(t, s.ObserveBytes(good))
+	bad := append([]byte(nil), good...)
+	bad[20] ^= 0xFF
+	assert.NoError(t, s.ObserveBytes(bad))
+	assert.Error(t, s.ObserveBytes(good[:20]))
+
+	assert.Equal(t, uint64(6), s.Frames)
+	assert.Equal(t, uint64(64+68+(18+5)+64+64+20), s.Bytes)
+	assert.Equal(t, uint64(1), s.Tagged)
+	assert.Equal(t, uint64(2), s.Runts)
+	assert.Equal(t, uint64(1), s.BadFCS)
+	assert.Equal(t, map[EtherType]uint64{
+		EtherTypeIPv4: 3,
+		EtherTypeIPv6: 1,
+		EtherTypeARP:  1,
+	}, s.ByEtherType)
+}
@@ -0,0 +1,175 @@
+// Copyright (c) 2022 0x9ef. All rights reserved.
+// Use of this source code is governed by an MIT license
+// that can be found in the LICENSE file.
+package ethernet
+
+import "errors"
+
+var (
+	// ErrUnknownPort is returned when the port has no VLAN membership configured.
+	ErrUnknownPort = errors.New("port has no VLAN membership")
+	// ErrVLANNotAllowed is returned when the frame VLAN is not a member of the egress port.
+	ErrVLANNotAllowed = errors.New("VLAN is not allowed on the port")
+	// ErrAlreadyAssigned is returned by AssignVLAN when the frame tag already carries a VID.
+	ErrAlreadyAssigned = errors.New("frame is already assigned to a VLAN")
+)
+
+type portMembership struct {
+	trunk     bool
+	vids      map[uint16]struct{}
+	native    uint16
+	hasNative bool
+}
+
+// VLANTable is a per port VLAN membership of a switch. An access port is a member
+// of a single VLAN and sends frames untagged, a trunk port carries a set
+// of VLANs and sends frames tagged. VLANTable is not safe for concurrent use.
+type VLANTable struct {
+	ports map[int]*portMembership
+}
+
+// NewVLANTable returns an empty VLANTable.
+func NewVLANTable() *VLANTable {
+	return &VLANTable{ports: make(map[int]*portMembership)}
+}
+
+// AddAccess configures port as an access port of the VLAN vid.
+func (t *VLANTable) AddAccess(port int, vid uint16) {
+	t.ports[port] = &portMembership{vids: map[uint16]struct{}{vid: {}}}
+}
+
+// AddTrunk configures port as a trunk port carrying VLANs vids.
+func (t *VLANTable) AddTrunk(port int, vids []uint16) {
+	m := &portMembership{trunk: true, vids: make(map[uint16]struct{}, len(vids))}
+	for _, vid := range vids {
+		m.vids[vid] = struct{}{}
+	}
+	t.ports[port] = m
+}
+
+// SetNativeVLAN sets the native VLAN of the trunk port, frames of the native VLAN
+// are sent untagged. Returns ErrUnknownPort if the port is not configured.
+func (t *VLANTable) SetNativeVLAN(port int, vid uint16) error {
+	m, ok := t.ports[port]
+	if !ok {
+		return ErrUnknownPort
+	}
+	m.native, m.hasNative = vid, true
+	return nil
+}
+
+// Egress applies the tagging rules of the port to the frame, and returns a copy
+// of the frame to send: untagged on the access port, tagged on the trunk port
+// (untagged if the frame belongs to the native VLAN of the trunk). Untagging
+// strips the outer tag only, the stacked tags (if any) are kept.
+// The frame is expected to carry the tag assigned on ingress, an untagged frame
+// is treated as a frame of VLAN 0. Returns ErrUnknownPort if the port is not
+// configured, and ErrVLANNotAllowed if the frame VLAN is not a member of the port.
+func (t *VLANTable) Egress(f *Frame, port int) (*Frame, error) {
+	m, ok := t.ports[port]
+	if !ok {
+		return nil, ErrUnknownPort
+	}
+	var vid uint16
+	if f.tag8021q != nil {
+		_, _, vid = Decode8021qTCI(f.tag8021q.TCI)
+	}
+	if _, ok := m.vids[vid]; !ok {
+		return nil, ErrVLANNotAllowed
+	}
+
+	out := new(Frame)
+	f.CopyTo(out)
+	if !m.trunk {
+		if out.tag8021q != nil {
+			out.popTag()
+		}
+	} else if m.hasNative {
+		out.UntagNative(m.native)
+	}
+	return out, nil
+}
+
+// AssignVLAN assigns the frame to the VLAN vid, as the switch ingress port does with its PVID.
+// The VID of a priority tagged frame (VID 0) is set preserving the priority, an untagged frame
+// gets a new 802.1Q tag with priority 0. Returns ErrAlreadyAssigned if the frame is tagged
+// with a non-zero VID, and ErrInvalidVLAN if vid is out of range.
+func (f *Frame) AssignVLAN(vid uint16) error {
+	if vid > maxVlan {
+		return ErrInvalidVLAN
+	}
+	if f.tag8021q == nil {
+		f.SetTag8021Q(&Tag8021Q{
+			TPID: uint16(EtherTypeVlan),
+			TCI:  Encode8021qTCI(0, 0, vid),
+		})
+		return nil
+	}
+	pcp, dei, cur := Decode8021qTCI(f.tag8021q.TCI)
+	if cur != 0 {
+		return ErrAlreadyAssigned
+	}
+	f.tag8021q.TCI = Encode8021qTCI(pcp, dei, vid)
+	f.invalidate()
+	return nil
+}
+
+// Remark rewrites the priority of the outer tag to pcp preserving its DEI and VID,
+// as the switch does on 802.1p remarking. An untagged frame gets a priority tag (VID 0).
+// Returns ErrInvalidPCP if pcp is above HighestPCP, the frame is not modified then.
+func (f *Frame) Remark(pcp PCP) error {
+	if pcp > HighestPCP {
+		return ErrInvalidPCP
+	}
+	if f.tag8021q == nil {
+		f.SetTag8021Q(&Tag8021Q{
+			TPID: uint16(EtherTypeVlan),
+			TCI:  Encode8021qTCI(pcp, 0, NativeVlan),
+		})
+		return nil
+	}
+	_, dei, vid := Decode8021qTCI(f.tag8021q.TCI)
+	f.tag8021q.TCI = Encode8021qTCI(pcp, dei, vid)
+	f.invalidate()
+	return nil
+}
+
+// VLANKey returns the VID of the outer tag, which identifies the broadcast domain of the frame,
+// untagged and priority tagged frames are in NativeVlan.
+func (f *Frame) VLANKey() uint16 {
+	if f.tag8021q == nil {
+		return NativeVlan
+	}
+	_, _, vid := Decode8021qTCI(f.tag8021q.TCI)
+	return vid
+}
+
+// IsNativeVLAN reports whether the frame is untagged or priority tagged (VID NativeVlan).
+func (f *Frame) IsNativeVLAN() bool { return f.VLANKey() == NativeVlan }
+
+// UntagNative strips the outer tag if its VID is the native VID of the trunk port,
+// the first stacked tag (if any) becomes the outer tag. Reports whether the tag is stripped.
+func (f *Frame) UntagNative(nativeVID uint16) bool {
+	if f.tag8021q == nil {
+		return false
+	}
+	if _, _, vid := Decode8021qTCI(f.tag8021q.TCI); vid != nativeVID {
+		return false
+	}
+	f.popTag()
+	return true
+}
+
+// popTag strips the outer tag, the first stacked tag (if any) becomes the outer tag.
+func (f *Frame) popTag() {
+	f.tag8021q = nil
+	if len(f.inner) > 0 {
+		tag := f.inner[0]
+		f.tag8021q = &tag
+		f.inner = f.inner[1:]
+		if len(f.inner) == 0 {
+			f.inner = nil
+		}
+	}
+	f.invalidate()
+}
@@ -0,0 +1,289 @@
+package ethernet
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVLANTableEgress(t *testing.T) {
+	table := NewVLANTable()
+	table.AddAccess(1, 10)
+	table.AddTrunk(2, []uint16{10, 20})
+
+	src := HardwareAddr{127, 127, 127, 50, 50, 50}
+	dst := HardwareAddr{255, 255, 255, 50, 50, 50}
+
+	type suite struct {
+		name       string
+		vid        uint16
+		port       int
+		wantErr    error
+		wantTagged bool
+	}
+
+	testCases := []suite{
+		{name: "positive_access_untag", vid: 10, port: 1},
+		{name: "positive_trunk_keep_tag", vid: 20, port: 2, wantTagged: true},
+		{name: "negative_access_other_vlan", vid: 20, port: 1, wantErr: ErrVLANNotAllowed},
+		{name: "negative_trunk_not_allowed", vid: 30, port: 2, wantErr: ErrVLANNotAllowed},
+		{name: "negative_unknown_port", vid: 10, port: 3, wantErr: ErrUnknownPort},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			f := NewFrame(src, dst, EtherTypeIPv4, []byte("HELLO"), WithVLAN(PcpVI, 0, tc.vid))
+			out, err := table.Egress(f, tc.port)
+			if tc.wantErr != nil {
+				assert.Equal(t, tc.wantErr, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.NotNil(t, f.Tag8021Q(), "source frame must not be modified")
+			assert.Equal(t, tc.wantTagged, out.Tag8021Q() != nil)
+			if tc.wantTagged {
+				assert.Equal(t, *f.Tag8021Q(), *out.Tag8021Q())
+			}
+			assert.Equal(t, f.Payload(), out.Payload())
+		})
+	}
+}
+
+func TestVLANTableEgressAccessQinQ(t *testing.T) {
+	table := NewVLANTable()
+	table.AddAccess(1, 100)
+
+	src := HardwareAddr{127, 127, 127, 50, 50, 50}
+	dst := HardwareAddr{255, 255, 255, 50, 50, 50}
+	f := NewFrame(src, dst, EtherTypeIPv4, []byte("HELLO"), WithVLAN(PcpVI, 0, 100))
+	f.Tag8021Q().TPID = uint16(EtherTypeQinQ)
+	inner := Tag8021Q{TPID: uint16(EtherTypeVlan), TCI: Encode8021qTCI(PcpBE, 0, 200)}
+	f.SetInnerTags([]Tag8021Q{inner})
+
+	out, err := table.Egress(f, 1)
+	if !assert.NoError(t, err) {
+		return
+	}
+	// the S-Tag is stripped, the C-Tag becomes the outer tag
+	if assert.NotNil(t, out.Tag8021Q()) {
+		assert.Equal(t, inner, *out.Tag8021Q())
+	}
+	assert.Nil(t, out.InnerTags())
+	assert.Len(t, f.InnerTags(), 1, "source frame must not be modified")
+
+	var decoded Frame
+	assert.NoError(t, Unmarshal(out.MarshalReadOnly(), &decoded))
+	if assert.NotNil(t, decoded.Tag8021Q()) {
+		assert.Equal(t, inner, *decoded.Tag8021Q())
+	}
+	assert.Nil(t, decoded.InnerTags())
+}
+
+func TestFrameAssignVLAN(t *testing.T) {
+	src := HardwareAddr{127, 127, 127, 50, 50, 50}
+	dst := HardwareAddr{255, 255, 255, 50, 50, 50}
+
+	type suite struct {
+		name    string
+		opts    []FrameOption
+		vid     uint16
+		wantErr error
+		wantPCP PCP
+		wantDEI uint16
+		wantVID uint16
+	}
+
+	testCases := []suite{
+		{
+			name:    "positive_untagged",
+			vid:     10,
+			wantVID: 10,
+		},
+		{
+			name:    "positive_priority_tagged",
+			opts:    []FrameOption{WithVLAN(PcpVO, 1, 0)},
+			vid:     10,
+			wantPCP: PcpVO,
+			wantDEI: 1,
+			wantVID: 10,
+		},
+		{
+			name:    "negative_already_assigned",
+			opts:    []FrameOption{WithVLAN(PcpVO, 0, 20)},
+			vid:     10,
+			wantErr: ErrAlreadyAssigned,
+			wantPCP: PcpVO,
+			wantVID: 20,
+		},
+		{
+			name:    "negative_invalid_vid",
+			vid:     maxVlan + 1,
+			wantErr: ErrInvalidVLAN,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			f := NewFrame(src, dst, EtherTypeIPv4, []byte("HELLO"), tc.opts...)
+			assert.Equal(t, tc.wantErr, f.AssignVLAN(tc.vid))
+			if tc.wantErr == ErrInvalidVLAN {
+				assert.Nil(t, f.Tag8021Q())
+				return
+			}
+			if assert.NotNil(t, f.Tag8021Q()) {
+				assert.Equal(t, uint16(EtherTypeVlan), f.Tag8021Q().TPID)
+				pcp, dei, vid := Decode8021qTCI(f.Tag8021Q().TCI)
+				assert.Equal(t, tc.wantPCP, pcp)
+				assert.Equal(t, tc.wantDEI, dei)
+				assert.Equal(t, tc.wantVID, vid)
+			}
+		})
+	}
+}
+
+func TestFrameRemark(t *testing.T) {
+	src := HardwareAddr{127, 127, 127, 50, 50, 50}
+	dst := HardwareAddr{255, 255, 255, 50, 50, 50}
+
+	type suite struct {
+		name    string
+		opts    []FrameOption
+		pcp     PCP
+		wantErr error
+		wantPCP PCP
+		wantDEI uint16
+		wantVID uint16
+	}
+
+	testCases := []suite{
+		{
+			name:    "positive_tagged",
+			opts:    []FrameOption{WithVLAN(PcpBE, 1, 100)},
+			pcp:     PcpVO,
+			wantPCP: PcpVO,
+			wantDEI: 1,
+			wantVID: 100,
+		},
+		{
+			name:    "positive_untagged",
+			pcp:     PcpVI,
+			wantPCP: PcpVI,
+			wantVID: NativeVlan,
+		},
+		{
+			name:    "positive_network_control",
+			opts:    []FrameOption{WithVLAN(PcpBE, 0, 100)},
+			pcp:     PcpNC,
+			wantPCP: PcpNC,
+			wantVID: 100,
+		},
+		{
+			name:    "negative_invalid_pcp",
+			opts:    []FrameOption{WithVLAN(PcpBE, 1, 100)},
+			pcp:     HighestPCP + 1,
+			wantErr: ErrInvalidPCP,
+			wantPCP: PcpBE,
+			wantDEI: 1,
+			wantVID: 100,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			f := NewFrame(src, dst, EtherTypeIPv4, []byte("HELLO"), tc.opts...)
+			assert.Equal(t, tc.wantErr, f.Remark(tc.pcp))
+			if assert.NotNil(t, f.Tag8021Q()) {
+				assert.Equal(t, uint16(EtherTypeVlan), f.Tag8021Q().TPID)
+				pcp, dei, vid := Decode8021qTCI(f.Tag8021Q().TCI)
+				assert.Equal(t, tc.wantPCP, pcp)
+				assert.Equal(t, tc.wantDEI, dei)
+				assert.Equal(t, tc.wantVID, vid)
+			}
+		})
+	}
+
+	// invalid PCP doesn't add a tag
+	f := NewFrame(src, dst, EtherTypeIPv4, []byte("HELLO"))
+	assert.Equal(t, ErrInvalidPCP, f.Remark(HighestPCP+1))
+	assert.Nil(t, f.Tag8021Q())
+}
+
+func TestFrameNativeVLAN(t *testing.T) {
+	src := HardwareAddr{127, 127, 127, 50, 50, 50}
+	dst := HardwareAddr{255, 255, 255, 50, 50, 50}
+
+	type suite struct {
+		name         string
+		opts         []FrameOption
+		native       uint16
+		wantIsNative bool
+		wantStripped bool
+	}
+
+	testCases := []suite{
+		{name: "positive_untagged", native: 10, wantIsNative: true},
+		{name: "positive_priority_tagged", opts: []FrameOption{WithVLAN(PcpVO, 0, NativeVlan)}, native: 10, wantIsNative: true},
+		{name: "positive_native_vid_match", opts: []FrameOption{WithVLAN(PcpVO, 0, 10)}, native: 10, wantStripped: true},
+		{name: "negative_native_vid_mismatch", opts: []FrameOption{WithVLAN(PcpVO, 0, 20)}, native: 10},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			f := NewFrame(src, dst, EtherTypeIPv4, []byte("HELLO"), tc.opts...)
+			tagged := f.Tag8021Q() != nil
+			assert.Equal(t, tc.wantIsNative, f.IsNativeVLAN())
+			assert.Equal(t, tc.wantStripped, f.UntagNative(tc.native))
+			assert.Equal(t, tagged && !tc.wantStripped, f.Tag8021Q() != nil)
+		})
+	}
+
+	// the inner tag becomes the outer one
+	f := NewFrame(src, dst, EtherTypeIPv4, []byte("HELLO"), WithVLAN(PcpVO, 0, 10))
+	inner := Tag8021Q{TPID: uint16(EtherTypeVlan), TCI: Encode8021qTCI(PcpBE, 0, 200)}
+	f.SetInnerTags([]Tag8021Q{inner})
+	assert.True(t, f.UntagNative(10))
+	assert.Equal(t, inner, *f.Tag8021Q())
+	assert.Nil(t, f.InnerTags())
+}
+
+func TestVLANTableEgressNative(t *testing.T) {
+	table := NewVLANTable()
+	table.AddTrunk(1, []uint16{10, 20})
+	assert.NoError(t, table.SetNativeVLAN(1, 10))
+	assert.Equal(t, ErrUnknownPort, table.SetNativeVLAN(2, 10))
+
+	src := HardwareAddr{127, 127, 127, 50, 50, 50}
+	dst := HardwareAddr{255, 255, 255, 50, 50, 50}
+
+	out, err := table.Egress(NewFrame(src, dst, EtherTypeIPv4, nil, WithVLAN(PcpVI, 0, 10)), 1)
+	assert.NoError(t, err)
+	assert.Nil(t, out.Tag8021Q(), "native VLAN is sent untagged")
+
+	out, err = table.Egress(NewFrame(src, dst, EtherTypeIPv4, nil, WithVLAN(PcpVI, 0, 20)), 1)
+	assert.NoError(t, err)
+	assert.NotNil(t, out.Tag8021Q())
+}
+
+func TestFrameVLANKey(t *testing.T) {
+	src := HardwareAddr{127, 127, 127, 50, 50, 50}
+	dst := HardwareAddr{255, 255, 255, 50, 50, 50}
+
+	type suite struct {
+		name    string
+		opts    []FrameOption
+		wantKey uint16
+	}
+
+	testCases := []suite{
+		{name: "positive_untagged", wantKey: NativeVlan},
+		{name: "positive_priority_tagged", opts: []FrameOption{WithVLAN(PcpVO, 0, 0)}, wantKey: NativeVlan},
+		{name: "positive_tagged", opts: []FrameOption{WithVLAN(PcpVO, 1, 100)}, wantKey: 100},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			f := NewFrame(src, dst, EtherTypeIPv4, []byte("HELLO"), tc.opts...)
+			assert.Equal(t, tc.wantKey, f.VLANKey())
+		})
+	}
+}
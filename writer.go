@@ -0,0 +1,124 @@
+// Copyright (c) 2022 0x9ef. All rights reserved.
+// Use of this source code is governed by an MIT license
+// that can be found in the LICENSE file.
+package ethernet
+
+import (
+	"compress/gzip"
+	"errors"
+	"io"
+)
+
+var (
+	// ErrBadMagic is returned when the stream doesn't start with the frame log header.
+	ErrBadMagic = errors.New("bad frame log magic")
+	// ErrUnsupportedVersion is returned when the frame log format version is unknown.
+	ErrUnsupportedVersion = errors.New("unsupported frame log version")
+	// ErrRecordTooLarge is returned when the frame size doesn't fit into the length prefix.
+	ErrRecordTooLarge = errors.New("frame size doesn't fit into length prefix")
+	// ErrUnsupportedCompression is returned when the frame log compression is unknown.
+	ErrUnsupportedCompression = errors.New("unsupported frame log compression")
+)
+
+// The frame log header written once at the start of the stream:
+//
+//	magic   [3]byte "ETH"
+//	version byte    1
+//	prefix  byte    width of the length prefix in bytes (2 or 4)
+//	flags   byte    compression of the records following the header (see Compression)
+var frameLogMagic = [3]byte{'E', 'T', 'H'}
+
+// Compression is the compression of the frame log records, it's stored in the
+// format header, so the reader decompresses the records transparently.
+type Compression uint8
+
+const (
+	CompressNone Compression = 0
+	CompressGzip Compression = 1
+)
+
+const (
+	frameLogVersion    = 1
+	frameLogHeaderSize = 6
+)
+
+// FrameWriter writes length-delimited Ethernet frames preceded by the format header,
+// the stream can be read back with OpenFrameReader.
+type FrameWriter struct {
+	w         io.Writer
+	gz        *gzip.Writer // set if the records are compressed
+	lenPrefix int
+	buf       []byte
+}
+
+// NewFrameWriter writes the format header to w and returns a new FrameWriter,
+// lenPrefix is the width of the length prefix in bytes (2 or 4).
+func NewFrameWriter(w io.Writer, lenPrefix int) (*FrameWriter, error) {
+	return NewFrameWriterCompressed(w, lenPrefix, CompressNone)
+}
+
+// NewFrameWriterCompressed is like NewFrameWriter, but the records following the header
+// are compressed with c. The compressed stream must be finished with Close.
+// Returns ErrUnsupportedCompression if c is unknown.
+func NewFrameWriterCompressed(w io.Writer, lenPrefix int, c Compression) (*FrameWriter, error) {
+	if lenPrefix != 2 && lenPrefix != 4 {
+		return nil, ErrBadLenPrefix
+	}
+	if c != CompressNone && c != CompressGzip {
+		return nil, ErrUnsupportedCompression
+	}
+	hdr := [frameLogHeaderSize]byte{
+		frameLogMagic[0], frameLogMagic[1], frameLogMagic[2],
+		frameLogVersion,
+		byte(lenPrefix),
+		byte(c),
+	}
+	if _, err := w.Write(hdr[:]); err != nil {
+		return nil, err
+	}
+	fw := &FrameWriter{w: w, lenPrefix: lenPrefix}
+	if c == CompressGzip {
+		fw.gz = gzip.NewWriter(w)
+		fw.w = fw.gz
+	}
+	return fw, nil
+}
+
+// Flush writes the pending compressed records to the underlying writer.
+// It's a no-op if the records are not compressed.
+func (fw *FrameWriter) Flush() error {
+	if fw.gz == nil {
+		return nil
+	}
+	return fw.gz.Flush()
+}
+
+// Close finishes the compressed stream, it doesn't close the underlying writer.
+// It's a no-op if the records are not compressed.
+func (fw *FrameWriter) Close() error {
+	if fw.gz == nil {
+		return nil
+	}
+	return fw.gz.Close()
+}
+
+// WriteFrame marshals the frame and writes it with the length prefix.
+// Returns ErrRecordTooLarge if the frame size doesn't fit into the length prefix.
+func (fw *FrameWriter) WriteFrame(f *Frame) error {
+	sz := f.Size()
+	if fw.lenPrefix == 2 && sz > 0xFFFF {
+		return ErrRecordTooLarge
+	}
+
+	b := fw.buf[:0]
+	if fw.lenPrefix == 2 {
+		b = append(b, byte(sz>>8), byte(sz))
+	} else {
+		b = append(b, byte(sz>>24), byte(sz>>16), byte(sz>>8), byte(sz))
+	}
+	b = f.MarshalAppend(b)
+	fw.buf = b
+
+	_, err := fw.w.Write(b)
+	return err
+}
@@ -0,0 +1,93 @@
+package ethernet
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFrameWriterRoundTrip(t *testing.T) {
+	for _, lenPrefix := range []int{2, 4} {
+		var buf bytes.Buffer
+		fw, err := NewFrameWriter(&buf, lenPrefix)
+		assert.NoError(t, err)
+
+		f1 := NewFrame(HardwareAddr{127, 127, 127, 50, 50, 50}, HardwareAddr{255, 255, 255, 50, 50, 50}, EtherTypeIPv4, []byte("HELLO"))
+		f2 := NewFrame(HardwareAddr{127, 127, 127, 50, 50, 51}, HardwareAddr{255, 255, 255, 50, 50, 51}, EtherTypeIPv6, generatePayload(), WithVLAN(PcpVI, 0, 100))
+		assert.NoError(t, fw.WriteFrame(f1))
+		assert.NoError(t, fw.WriteFrame(f2))
+
+		fr, err := OpenFrameReader(&buf)
+		assert.NoError(t, err)
+		for _, f := range []*Frame{f1, f2} {
+			decoded, err := fr.ReadFrame()
+			assert.NoError(t, err)
+			assert.Equal(t, f.Source(), decoded.Source())
+			assert.Equal(t, f.Tag8021Q(), decoded.Tag8021Q())
+			assert.Equal(t, f.Payload(), decoded.Payload())
+			assert.Equal(t, f.FCS(), decoded.FCS())
+		}
+		_, err = fr.ReadFrame()
+		assert.Equal(t, io.EOF, err)
+	}
+}
+
+func TestFrameWriterCompressedRoundTrip(t *testing.T) {
+	var buf bytes.Buffer
+	fw, err := NewFrameWriterCompressed(&buf, 4, CompressGzip)
+	assert.NoError(t, err)
+
+	var frames []*Frame
+	for i := 0; i < 100; i++ {
+		f := NewFrame(HardwareAddr{127, 127, 127, 50, 50, byte(i)}, HardwareAddr{255, 255, 255, 50, 50, 50}, EtherTypeIPv4, bytes.Repeat([]byte("HELLO"), 100))
+		assert.NoError(t, fw.WriteFrame(f))
+		frames = append(frames, f)
+	}
+	assert.NoError(t, fw.Close())
+	assert.Equal(t, byte(CompressGzip), buf.Bytes()[frameLogHeaderSize-1])
+	assert.Less(t, buf.Len(), 100*frames[0].Size(), "records are compressed")
+
+	fr, err := OpenFrameReader(&buf)
+	assert.NoError(t, err)
+	for _, f := range frames {
+		decoded, err := fr.ReadFrame()
+		if !assert.NoError(t, err) {
+			return
+		}
+		assert.Equal(t, f.Source(), decoded.Source())
+		assert.Equal(t, f.Payload(), decoded.Payload())
+		assert.Equal(t, f.FCS(), decoded.FCS())
+	}
+	_, err = fr.ReadFrame()
+	assert.Equal(t, io.EOF, err)
+
+	_, err = NewFrameWriterCompressed(&buf, 4, Compression(2))
+	assert.Equal(t, ErrUnsupportedCompression, err)
+}
+
+func TestOpenFrameReaderBadHeader(t *testing.T) {
+	type suite struct {
+		name    string
+		data    []byte
+		wantErr error
+	}
+
+	testCases := []suite{
+		{name: "negative_magic", data: []byte{'P', 'C', 'P', 1, 2, 0}, wantErr: ErrBadMagic},
+		{name: "negative_version", data: []byte{'E', 'T', 'H', 2, 2, 0}, wantErr: ErrUnsupportedVersion},
+		{name: "negative_prefix", data: []byte{'E', 'T', 'H', 1, 3, 0}, wantErr: ErrBadLenPrefix},
+		{name: "negative_compression", data: []byte{'E', 'T', 'H', 1, 2, 2}, wantErr: ErrUnsupportedCompression},
+		{name: "negative_gzip_header", data: []byte{'E', 'T', 'H', 1, 2, 1, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0}, wantErr: gzip.ErrHeader},
+		{name: "negative_truncated", data: []byte{'E', 'T'}, wantErr: io.ErrUnexpectedEOF},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := OpenFrameReader(bytes.NewReader(tc.data))
+			assert.Equal(t, tc.wantErr, err)
+		})
+	}
+}